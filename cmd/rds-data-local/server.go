@@ -0,0 +1,239 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rdsdata"
+	"github.com/aws/aws-sdk-go-v2/service/rdsdata/types"
+	"github.com/aws/smithy-go"
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgconn"
+	"github.com/krotscheck/go-rds-driver/internal/dataapi"
+)
+
+// Backend database serving the Data API requests for a resource ARN.
+type Backend struct {
+	// ResourceARN of the cluster emulated by the backend.
+	ResourceARN string
+	// Driver of the backend, such as mysql or pgx.
+	Driver string
+	// DSN of the backend, passed to the driver.
+	DSN string
+}
+
+// NewServer of the Data API, opening the given backends.
+func NewServer(backends []Backend) (*Server, error) {
+	s := &Server{engines: map[string]*dataapi.Engine{}}
+	for _, backend := range backends {
+		db, err := sql.Open(backend.Driver, backend.DSN)
+		if err != nil {
+			s.Close()
+			return nil, fmt.Errorf("opening %s: %w", backend.ResourceARN, err)
+		}
+		engine := dataapi.NewEngine(db)
+		switch backend.Driver {
+		case "pgx", "postgres":
+			engine.Postgres = true
+			engine.NumberedPlaceholders = true
+		}
+		engine.Message = message
+		s.engines[backend.ResourceARN] = engine
+	}
+	return s, nil
+}
+
+// Server of the Data API's REST protocol.
+type Server struct {
+	engines map[string]*dataapi.Engine
+}
+
+var _ http.Handler = (*Server)(nil) // explicit compile time type check
+
+// Close the backends, rolling back any open transactions.
+func (s *Server) Close() {
+	for _, engine := range s.engines {
+		engine.Close()
+		_ = engine.DB.Close()
+	}
+}
+
+// ServeHTTP routes the Data API operations.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		s.writeError(w, &types.BadRequestException{Message: aws.String("Unsupported method " + r.Method)})
+		return
+	}
+	var (
+		response any
+		err      error
+	)
+	switch r.URL.Path {
+	case "/Execute":
+		response, err = s.execute(r)
+	case "/BatchExecute":
+		response, err = s.batchExecute(r)
+	case "/BeginTransaction":
+		response, err = s.beginTransaction(r)
+	case "/CommitTransaction":
+		response, err = s.commitTransaction(r)
+	case "/RollbackTransaction":
+		response, err = s.rollbackTransaction(r)
+	default:
+		err = &types.NotFoundException{Message: aws.String("Unsupported operation " + r.URL.Path)}
+	}
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Amzn-Requestid", dataapi.NewRequestID())
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+func (s *Server) execute(r *http.Request) (any, error) {
+	var request executeStatementRequest
+	engine, err := s.decode(r, &request, &request.ResourceArn)
+	if err != nil {
+		return nil, err
+	}
+	output, err := engine.ExecuteStatement(r.Context(), request.input())
+	if err != nil {
+		return nil, err
+	}
+	return newExecuteStatementResponse(output), nil
+}
+
+func (s *Server) batchExecute(r *http.Request) (any, error) {
+	var request batchExecuteStatementRequest
+	engine, err := s.decode(r, &request, &request.ResourceArn)
+	if err != nil {
+		return nil, err
+	}
+	output, err := engine.BatchExecuteStatement(r.Context(), request.input())
+	if err != nil {
+		return nil, err
+	}
+	return newBatchExecuteStatementResponse(output), nil
+}
+
+func (s *Server) beginTransaction(r *http.Request) (any, error) {
+	var request beginTransactionRequest
+	engine, err := s.decode(r, &request, &request.ResourceArn)
+	if err != nil {
+		return nil, err
+	}
+	output, err := engine.BeginTransaction(r.Context(), &rdsdata.BeginTransactionInput{
+		ResourceArn: aws.String(request.ResourceArn),
+		SecretArn:   aws.String(request.SecretArn),
+		Database:    aws.String(request.Database),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &beginTransactionResponse{TransactionID: aws.ToString(output.TransactionId)}, nil
+}
+
+func (s *Server) commitTransaction(r *http.Request) (any, error) {
+	var request endTransactionRequest
+	engine, err := s.decode(r, &request, &request.ResourceArn)
+	if err != nil {
+		return nil, err
+	}
+	output, err := engine.CommitTransaction(r.Context(), &rdsdata.CommitTransactionInput{
+		ResourceArn:   aws.String(request.ResourceArn),
+		SecretArn:     aws.String(request.SecretArn),
+		TransactionId: aws.String(request.TransactionID),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &endTransactionResponse{TransactionStatus: aws.ToString(output.TransactionStatus)}, nil
+}
+
+func (s *Server) rollbackTransaction(r *http.Request) (any, error) {
+	var request endTransactionRequest
+	engine, err := s.decode(r, &request, &request.ResourceArn)
+	if err != nil {
+		return nil, err
+	}
+	output, err := engine.RollbackTransaction(r.Context(), &rdsdata.RollbackTransactionInput{
+		ResourceArn:   aws.String(request.ResourceArn),
+		SecretArn:     aws.String(request.SecretArn),
+		TransactionId: aws.String(request.TransactionID),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &endTransactionResponse{TransactionStatus: aws.ToString(output.TransactionStatus)}, nil
+}
+
+// decode the request's body, returning the engine of the resource ARN it names.
+func (s *Server) decode(r *http.Request, request any, resourceARN *string) (*dataapi.Engine, error) {
+	if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+		return nil, &types.BadRequestException{Message: aws.String("Invalid request body: " + err.Error())}
+	}
+	engine, ok := s.engines[*resourceARN]
+	if !ok {
+		return nil, &types.BadRequestException{Message: aws.String(fmt.Sprintf("Database %s not found", *resourceARN))}
+	}
+	return engine, nil
+}
+
+// writeError as the Data API would, with the exception's code in the X-Amzn-ErrorType header.
+func (s *Server) writeError(w http.ResponseWriter, err error) {
+	requestID := dataapi.NewRequestID()
+	status := http.StatusBadRequest
+	code := "BadRequestException"
+	var apiErr smithy.APIError
+	switch {
+	case errors.As(err, &apiErr):
+		code = apiErr.ErrorCode()
+		switch apiErr.(type) {
+		case *types.TransactionNotFoundException, *types.NotFoundException:
+			status = http.StatusNotFound
+		case *types.ForbiddenException:
+			status = http.StatusForbidden
+		}
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		code = "StatementTimeoutException"
+	default:
+		status = http.StatusInternalServerError
+		code = "InternalServerErrorException"
+	}
+	log.Printf("%s: %s: %v", requestID, code, err)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Amzn-Errortype", code)
+	w.Header().Set("X-Amzn-Requestid", requestID)
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(&errorResponse{Message: errorMessage(err)})
+}
+
+// errorMessage of an exception, without its code.
+func errorMessage(err error) string {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.ErrorMessage()
+	}
+	return err.Error()
+}
+
+// message of an error returned by a backend, as worded by the Data API.
+func message(err error) string {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return fmt.Sprintf("ERROR: %s; SQLState: %s", pgErr.Message, pgErr.Code)
+	}
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Message
+	}
+	return err.Error()
+}
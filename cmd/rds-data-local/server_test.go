@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rdsdata"
+	"github.com/aws/aws-sdk-go-v2/service/rdsdata/types"
+	"github.com/krotscheck/go-rds-driver"
+	. "github.com/smartystreets/goconvey/convey"
+	"modernc.org/sqlite"
+)
+
+func init() {
+	// SQLite stands in for MySQL, which the driver detects from the version.
+	sqlite.MustRegisterDeterministicScalarFunction("version", 0, func(*sqlite.FunctionContext, []driver.Value) (driver.Value, error) {
+		return "8.0.28", nil
+	})
+}
+
+func Test_Server(t *testing.T) {
+	ctx := context.Background()
+
+	Convey("Server", t, func() {
+		const resourceARN = "arn:aws:rds:us-east-1:000000000000:cluster:sqlite"
+		server, err := NewServer([]Backend{{
+			ResourceARN: resourceARN,
+			Driver:      "sqlite",
+			DSN:         "file:" + filepath.Join(t.TempDir(), "test.db") + "?_pragma=busy_timeout(1000)",
+		}})
+		So(err, ShouldBeNil)
+		defer server.Close()
+		srv := httptest.NewServer(server)
+		defer srv.Close()
+
+		client := rdsdata.New(rdsdata.Options{
+			Region:       "us-east-1",
+			BaseEndpoint: aws.String(srv.URL),
+			Credentials:  aws.AnonymousCredentials{},
+		})
+		conf := rds.NewConfig(resourceARN, "secretARN", "database", "us-east-1")
+		db := sql.OpenDB(rds.NewConnector(rds.NewDriver(), client, conf))
+		defer db.Close()
+
+		_, err = db.ExecContext(ctx, "CREATE TABLE accounts (id INTEGER PRIMARY KEY AUTOINCREMENT, name VARCHAR(255) NOT NULL UNIQUE, balance DOUBLE, avatar BLOB)")
+		So(err, ShouldBeNil)
+		result, err := db.ExecContext(ctx, "INSERT INTO accounts (name, balance, avatar) VALUES (?, ?, ?)", "alice", 10.5, []byte{0x01, 0x02})
+		So(err, ShouldBeNil)
+		id, err := result.LastInsertId()
+		So(err, ShouldBeNil)
+		So(id, ShouldEqual, 1)
+
+		Convey("Returns records", func() {
+			var name string
+			var balance float64
+			var avatar []byte
+			var missing sql.NullString
+			So(db.QueryRowContext(ctx, "SELECT name, balance, avatar, NULL FROM accounts WHERE id = ?", id).Scan(&name, &balance, &avatar, &missing), ShouldBeNil)
+			So(name, ShouldEqual, "alice")
+			So(balance, ShouldEqual, 10.5)
+			So(avatar, ShouldResemble, []byte{0x01, 0x02})
+			So(missing.Valid, ShouldBeFalse)
+		})
+
+		Convey("Commits and rolls back transactions", func() {
+			tx, err := db.BeginTx(ctx, nil)
+			So(err, ShouldBeNil)
+			_, err = tx.ExecContext(ctx, "UPDATE accounts SET balance = balance + 1")
+			So(err, ShouldBeNil)
+			So(tx.Commit(), ShouldBeNil)
+
+			tx, err = db.BeginTx(ctx, nil)
+			So(err, ShouldBeNil)
+			_, err = tx.ExecContext(ctx, "DELETE FROM accounts")
+			So(err, ShouldBeNil)
+			So(tx.Rollback(), ShouldBeNil)
+
+			var balance float64
+			So(db.QueryRowContext(ctx, "SELECT balance FROM accounts").Scan(&balance), ShouldBeNil)
+			So(balance, ShouldEqual, 11.5)
+		})
+
+		Convey("Executes batches", func() {
+			output, err := client.BatchExecuteStatement(ctx, &rdsdata.BatchExecuteStatementInput{
+				ResourceArn: aws.String(resourceARN),
+				SecretArn:   aws.String("secretARN"),
+				Sql:         aws.String("INSERT INTO accounts (name) VALUES (:name)"),
+				ParameterSets: [][]types.SqlParameter{
+					{{Name: aws.String("name"), Value: &types.FieldMemberStringValue{Value: "bob"}}},
+					{{Name: aws.String("name"), Value: &types.FieldMemberStringValue{Value: "carol"}}},
+				},
+			})
+			So(err, ShouldBeNil)
+			So(output.UpdateResults, ShouldHaveLength, 2)
+			So(output.UpdateResults[1].GeneratedFields, ShouldResemble, []types.Field{&types.FieldMemberLongValue{Value: 3}})
+		})
+
+		Convey("Returns errors in the shape of the Data API", func() {
+			_, err := db.ExecContext(ctx, "INSERT INTO accounts (name) VALUES (?)", "alice")
+			var stmtErr *rds.StatementError
+			So(errors.As(err, &stmtErr), ShouldBeTrue)
+			So(stmtErr.RequestID, ShouldNotBeEmpty)
+			var badRequest *types.BadRequestException
+			So(errors.As(err, &badRequest), ShouldBeTrue)
+			So(badRequest.ErrorMessage(), ShouldContainSubstring, "UNIQUE constraint failed")
+
+			_, err = client.CommitTransaction(ctx, &rdsdata.CommitTransactionInput{
+				ResourceArn:   aws.String(resourceARN),
+				SecretArn:     aws.String("secretARN"),
+				TransactionId: aws.String("missing"),
+			})
+			var notFound *types.TransactionNotFoundException
+			So(errors.As(err, &notFound), ShouldBeTrue)
+
+			_, err = client.BeginTransaction(ctx, &rdsdata.BeginTransactionInput{
+				ResourceArn: aws.String("unknown"),
+				SecretArn:   aws.String("secretARN"),
+			})
+			So(errors.As(err, &badRequest), ShouldBeTrue)
+		})
+	})
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rdsdata"
+	"github.com/aws/aws-sdk-go-v2/service/rdsdata/types"
+	"github.com/krotscheck/go-rds-driver/internal/dataapi"
+)
+
+// The JSON documents of the Data API's REST protocol, as sent and expected by the AWS SDKs.
+
+type executeStatementRequest struct {
+	ResourceArn           string              `json:"resourceArn"`
+	SecretArn             string              `json:"secretArn"`
+	SQL                   string              `json:"sql"`
+	Database              string              `json:"database,omitempty"`
+	Schema                string              `json:"schema,omitempty"`
+	Parameters            []dataapi.Parameter `json:"parameters,omitempty"`
+	TransactionID         string              `json:"transactionId,omitempty"`
+	IncludeResultMetadata bool                `json:"includeResultMetadata"`
+	ContinueAfterTimeout  bool                `json:"continueAfterTimeout"`
+	FormatRecordsAs       string              `json:"formatRecordsAs,omitempty"`
+}
+
+type executeStatementResponse struct {
+	ColumnMetadata         []dataapi.ColumnMetadata `json:"columnMetadata,omitempty"`
+	FormattedRecords       *string                  `json:"formattedRecords,omitempty"`
+	GeneratedFields        []dataapi.Field          `json:"generatedFields,omitempty"`
+	NumberOfRecordsUpdated int64                    `json:"numberOfRecordsUpdated"`
+	Records                [][]dataapi.Field        `json:"records,omitempty"`
+}
+
+type batchExecuteStatementRequest struct {
+	ResourceArn   string                `json:"resourceArn"`
+	SecretArn     string                `json:"secretArn"`
+	SQL           string                `json:"sql"`
+	Database      string                `json:"database,omitempty"`
+	Schema        string                `json:"schema,omitempty"`
+	ParameterSets [][]dataapi.Parameter `json:"parameterSets,omitempty"`
+	TransactionID string                `json:"transactionId,omitempty"`
+}
+
+type updateResult struct {
+	GeneratedFields []dataapi.Field `json:"generatedFields"`
+}
+
+type batchExecuteStatementResponse struct {
+	UpdateResults []updateResult `json:"updateResults"`
+}
+
+type beginTransactionRequest struct {
+	ResourceArn string `json:"resourceArn"`
+	SecretArn   string `json:"secretArn"`
+	Database    string `json:"database,omitempty"`
+	Schema      string `json:"schema,omitempty"`
+}
+
+type beginTransactionResponse struct {
+	TransactionID string `json:"transactionId"`
+}
+
+type endTransactionRequest struct {
+	ResourceArn   string `json:"resourceArn"`
+	SecretArn     string `json:"secretArn"`
+	TransactionID string `json:"transactionId"`
+}
+
+type endTransactionResponse struct {
+	TransactionStatus string `json:"transactionStatus"`
+}
+
+type errorResponse struct {
+	Message string `json:"message"`
+}
+
+func (r *executeStatementRequest) input() *rdsdata.ExecuteStatementInput {
+	return &rdsdata.ExecuteStatementInput{
+		ResourceArn:           aws.String(r.ResourceArn),
+		SecretArn:             aws.String(r.SecretArn),
+		Sql:                   aws.String(r.SQL),
+		Database:              aws.String(r.Database),
+		Parameters:            dataapi.ToParameters(r.Parameters),
+		TransactionId:         aws.String(r.TransactionID),
+		IncludeResultMetadata: r.IncludeResultMetadata,
+		ContinueAfterTimeout:  r.ContinueAfterTimeout,
+		FormatRecordsAs:       types.RecordsFormatType(r.FormatRecordsAs),
+	}
+}
+
+func newExecuteStatementResponse(output *rdsdata.ExecuteStatementOutput) *executeStatementResponse {
+	response := &executeStatementResponse{
+		ColumnMetadata:         dataapi.FromColumnMetadata(output.ColumnMetadata),
+		FormattedRecords:       output.FormattedRecords,
+		GeneratedFields:        dataapi.FromFields(output.GeneratedFields),
+		NumberOfRecordsUpdated: output.NumberOfRecordsUpdated,
+	}
+	for _, record := range output.Records {
+		response.Records = append(response.Records, dataapi.FromFields(record))
+	}
+	return response
+}
+
+func (r *batchExecuteStatementRequest) input() *rdsdata.BatchExecuteStatementInput {
+	input := &rdsdata.BatchExecuteStatementInput{
+		ResourceArn:   aws.String(r.ResourceArn),
+		SecretArn:     aws.String(r.SecretArn),
+		Sql:           aws.String(r.SQL),
+		Database:      aws.String(r.Database),
+		TransactionId: aws.String(r.TransactionID),
+	}
+	for _, params := range r.ParameterSets {
+		input.ParameterSets = append(input.ParameterSets, dataapi.ToParameters(params))
+	}
+	return input
+}
+
+func newBatchExecuteStatementResponse(output *rdsdata.BatchExecuteStatementOutput) *batchExecuteStatementResponse {
+	response := &batchExecuteStatementResponse{UpdateResults: []updateResult{}}
+	for _, result := range output.UpdateResults {
+		response.UpdateResults = append(response.UpdateResults, updateResult{GeneratedFields: dataapi.FromFields(result.GeneratedFields)})
+	}
+	return response
+}
@@ -5,21 +5,26 @@ import (
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/rdsdata"
 	"github.com/aws/aws-sdk-go-v2/service/rdsdata/types"
+	"github.com/golang/mock/gomock"
 	"github.com/krotscheck/go-rds-driver"
 	"os"
 	"strings"
 )
 
 var TestMysqlConfig *rds.Config
+var TestMysql8Config *rds.Config
 var TestPostgresConfig *rds.Config
 
 type TestConfig struct {
 	MysqlDBName    string
 	MysqlARN       string
+	Mysql8DBName   string
+	Mysql8ARN      string
 	PostgresDBName string
 	PostgresARN    string
 	SecretARN      string
 	AWSRegion      string
+	EndpointURL    string
 }
 
 func init() {
@@ -31,6 +36,10 @@ func init() {
 			conf.MysqlDBName = pair[1]
 		case "RDS_MYSQL_ARN":
 			conf.MysqlARN = pair[1]
+		case "RDS_MYSQL8_DB_NAME":
+			conf.Mysql8DBName = pair[1]
+		case "RDS_MYSQL8_ARN":
+			conf.Mysql8ARN = pair[1]
 		case "RDS_POSTGRES_DB_NAME":
 			conf.PostgresDBName = pair[1]
 		case "RDS_POSTGRES_ARN":
@@ -39,13 +48,21 @@ func init() {
 			conf.SecretARN = pair[1]
 		case "AWS_REGION":
 			conf.AWSRegion = pair[1]
+		case "RDS_ENDPOINT_URL":
+			conf.EndpointURL = pair[1]
 		}
 	}
 
 	TestMysqlConfig = rds.NewConfig(conf.MysqlARN, conf.SecretARN, conf.MysqlDBName, conf.AWSRegion)
 	TestMysqlConfig.SplitMulti = true
+	TestMysqlConfig.EndpointURL = conf.EndpointURL
+	TestMysql8Config = rds.NewConfig(conf.Mysql8ARN, conf.SecretARN, conf.Mysql8DBName, conf.AWSRegion)
+	TestMysql8Config.SplitMulti = true
+	TestMysql8Config.EndpointURL = conf.EndpointURL
+	TestMysql8Config.Dialect = rds.DialectNameMySQL8
 	TestPostgresConfig = rds.NewConfig(conf.PostgresARN, conf.SecretARN, conf.PostgresDBName, conf.AWSRegion)
 	TestPostgresConfig.SplitMulti = true
+	TestPostgresConfig.EndpointURL = conf.EndpointURL
 }
 
 // ExpectWakeup can be used whenever we're mocking out a new connection
@@ -67,3 +84,23 @@ func ExpectWakeup(mockRDS *MockAWSClientInterface, conf *rds.Config) {
 			},
 		}, nil)
 }
+
+// statementMatcher matches ExecuteStatementInput instances by their SQL.
+type statementMatcher struct {
+	sql string
+}
+
+func (m statementMatcher) Matches(x interface{}) bool {
+	input, ok := x.(*rdsdata.ExecuteStatementInput)
+	return ok && aws.ToString(input.Sql) == m.sql
+}
+
+func (m statementMatcher) String() string {
+	return "executes " + m.sql
+}
+
+// ExpectStatement can be used whenever we're mocking out the execution of a specific SQL statement
+func ExpectStatement(mockRDS *MockAWSClientInterface, sql string) *gomock.Call {
+	return mockRDS.EXPECT().
+		ExecuteStatement(gomock.Any(), statementMatcher{sql: sql})
+}
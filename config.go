@@ -4,26 +4,79 @@ import (
 	"fmt"
 	"net/url"
 	"strconv"
+	"time"
 )
 
 const (
-	keyResourceARN = "resource_arn"
-	keySecretARN   = "secret_arn"
-	keyDatabase    = "database"
-	keyAWSRegion   = "aws_region"
-	keyParseTime   = "parse_time"
-	keySplitMulti  = "split_multi"
+	keyResourceARN      = "resource_arn"
+	keySecretARN        = "secret_arn"
+	keyDatabase         = "database"
+	keyAWSRegion        = "aws_region"
+	keyEndpointURL      = "endpoint_url"
+	keyParseTime        = "parse_time"
+	keySplitMulti       = "split_multi"
+	keyAtomicMulti      = "atomic_multi"
+	keyCaptureReturning = "capture_returning"
+	keyDMLResultSets    = "dml_result_sets"
+	keyTimeAsDuration   = "time_as_duration"
+	keySetAsSlice       = "set_as_slice"
+	keyBitAsBytes       = "bit_as_bytes"
+	keyZeroDate         = "zero_date"
+	keyDialect          = "dialect"
+	keyCommitTimeout    = "commit_timeout"
+	keyTxKeepAlive      = "tx_keepalive"
+	keyQueryLog         = "query_log"
+	keySlowQuery        = "slow_query_threshold"
+)
+
+// ZeroDatePolicy describes how zero or otherwise invalid MySQL dates, such as 0000-00-00, are returned.
+type ZeroDatePolicy string
+
+const (
+	// ZeroDateError returns an error wrapping ErrInvalidDate.
+	ZeroDateError ZeroDatePolicy = "error"
+	// ZeroDateNull returns the value as NULL.
+	ZeroDateNull ZeroDatePolicy = "null"
+	// ZeroDateZeroTime returns the zero time.Time.
+	ZeroDateZeroTime ZeroDatePolicy = "zero_time"
+	// ZeroDateString returns the raw string sent by RDS.
+	ZeroDateString ZeroDatePolicy = "string"
 )
 
 // Config struct used to provide AWS Configuration Credentials
 type Config struct {
-	ResourceArn string
-	SecretArn   string
-	Database    string
-	AWSRegion   string
-	ParseTime   bool
-	SplitMulti  bool
-	Custom      map[string][]string
+	ResourceArn      string
+	SecretArn        string
+	Database         string
+	AWSRegion        string
+	EndpointURL      string
+	ParseTime        bool
+	SplitMulti       bool
+	AtomicMulti      bool
+	CaptureReturning bool
+	DMLResultSets    bool
+	TimeAsDuration   bool
+	SetAsSlice       bool
+	BitAsBytes       bool
+	ZeroDate         ZeroDatePolicy
+	Dialect          string
+	CommitTimeout    time.Duration
+	TxKeepAlive      time.Duration
+	Custom           map[string][]string
+
+	// Converters registered here take priority over the DefaultConverters. They cannot be set via the DSN.
+	Converters *ConverterRegistry
+	// Logger for the driver's events, defaulting to slog.Default(). It cannot be set via the DSN.
+	Logger Logger
+	// LogLevels of the driver's events, defaulting to DefaultLogLevels. They cannot be set via the DSN.
+	LogLevels *LogLevels
+	// Hooks called around every request to the Data API. They cannot be set via the DSN.
+	Hooks Hooks
+	// Metrics of the usage of the Data API, defaulting to DefaultMetrics(). They cannot be set via the DSN.
+	Metrics Metrics
+	// QueryLog of every executed query, disabled unless set. It may be enabled via the DSN, using
+	// the defaults of NewQueryLog.
+	QueryLog *QueryLog
 }
 
 // ToDSN converts the config to a DSN string
@@ -35,6 +88,45 @@ func (o *Config) ToDSN() string {
 	v.Add(keyAWSRegion, o.AWSRegion)
 	v.Add(keyParseTime, strconv.FormatBool(o.ParseTime))
 	v.Add(keySplitMulti, strconv.FormatBool(o.SplitMulti))
+	if o.TimeAsDuration {
+		v.Add(keyTimeAsDuration, strconv.FormatBool(o.TimeAsDuration))
+	}
+	if o.SetAsSlice {
+		v.Add(keySetAsSlice, strconv.FormatBool(o.SetAsSlice))
+	}
+	if o.BitAsBytes {
+		v.Add(keyBitAsBytes, strconv.FormatBool(o.BitAsBytes))
+	}
+	if o.AtomicMulti {
+		v.Add(keyAtomicMulti, strconv.FormatBool(o.AtomicMulti))
+	}
+	if o.CaptureReturning {
+		v.Add(keyCaptureReturning, strconv.FormatBool(o.CaptureReturning))
+	}
+	if o.DMLResultSets {
+		v.Add(keyDMLResultSets, strconv.FormatBool(o.DMLResultSets))
+	}
+	if o.EndpointURL != "" {
+		v.Add(keyEndpointURL, o.EndpointURL)
+	}
+	if o.ZeroDate != "" {
+		v.Add(keyZeroDate, string(o.ZeroDate))
+	}
+	if o.Dialect != "" {
+		v.Add(keyDialect, o.Dialect)
+	}
+	if o.CommitTimeout > 0 {
+		v.Add(keyCommitTimeout, o.CommitTimeout.String())
+	}
+	if o.TxKeepAlive > 0 {
+		v.Add(keyTxKeepAlive, o.TxKeepAlive.String())
+	}
+	if o.QueryLog != nil {
+		v.Add(keyQueryLog, "true")
+		if o.QueryLog.SlowThreshold > 0 {
+			v.Add(keySlowQuery, o.QueryLog.SlowThreshold.String())
+		}
+	}
 
 	for k, values := range o.Custom {
 		for _, value := range values {
@@ -73,6 +165,8 @@ func NewConfigFromDSN(dsn string) (conf *Config, err error) {
 			conf.Database = values.Get(keyDatabase)
 		case keyAWSRegion:
 			conf.AWSRegion = values.Get(keyAWSRegion)
+		case keyEndpointURL:
+			conf.EndpointURL = values.Get(keyEndpointURL)
 		case keyParseTime:
 			// Swallow the error here because default is fine.
 			parseTime, _ := strconv.ParseBool(values.Get(keyParseTime))
@@ -81,6 +175,57 @@ func NewConfigFromDSN(dsn string) (conf *Config, err error) {
 			// Swallow the error here because default is fine.
 			splitMulti, _ := strconv.ParseBool(values.Get(keySplitMulti))
 			conf.SplitMulti = splitMulti
+		case keyAtomicMulti:
+			// Swallow the error here because default is fine.
+			atomicMulti, _ := strconv.ParseBool(values.Get(keyAtomicMulti))
+			conf.AtomicMulti = atomicMulti
+		case keyCaptureReturning:
+			// Swallow the error here because default is fine.
+			captureReturning, _ := strconv.ParseBool(values.Get(keyCaptureReturning))
+			conf.CaptureReturning = captureReturning
+		case keyDMLResultSets:
+			// Swallow the error here because default is fine.
+			dmlResultSets, _ := strconv.ParseBool(values.Get(keyDMLResultSets))
+			conf.DMLResultSets = dmlResultSets
+		case keyTimeAsDuration:
+			// Swallow the error here because default is fine.
+			timeAsDuration, _ := strconv.ParseBool(values.Get(keyTimeAsDuration))
+			conf.TimeAsDuration = timeAsDuration
+		case keySetAsSlice:
+			// Swallow the error here because default is fine.
+			setAsSlice, _ := strconv.ParseBool(values.Get(keySetAsSlice))
+			conf.SetAsSlice = setAsSlice
+		case keyBitAsBytes:
+			// Swallow the error here because default is fine.
+			bitAsBytes, _ := strconv.ParseBool(values.Get(keyBitAsBytes))
+			conf.BitAsBytes = bitAsBytes
+		case keyZeroDate:
+			switch policy := ZeroDatePolicy(values.Get(keyZeroDate)); policy {
+			case ZeroDateError, ZeroDateNull, ZeroDateZeroTime, ZeroDateString:
+				conf.ZeroDate = policy
+			default:
+				return nil, ErrInvalidZeroDatePolicy
+			}
+		case keyDialect:
+			conf.Dialect = values.Get(keyDialect)
+		case keyCommitTimeout:
+			// Swallow the error here because default is fine, as are negative durations.
+			commitTimeout, _ := time.ParseDuration(values.Get(keyCommitTimeout))
+			conf.CommitTimeout = max(commitTimeout, 0)
+		case keyTxKeepAlive:
+			// Swallow the error here because default is fine, as are negative durations.
+			txKeepAlive, _ := time.ParseDuration(values.Get(keyTxKeepAlive))
+			conf.TxKeepAlive = max(txKeepAlive, 0)
+			if conf.TxKeepAlive >= TransactionIdleTimeout {
+				return nil, ErrInvalidTxKeepAlive
+			}
+		case keyQueryLog, keySlowQuery:
+			// Swallow the errors here because default is fine, as are negative durations.
+			queryLog, _ := strconv.ParseBool(values.Get(keyQueryLog))
+			slowQuery, _ := time.ParseDuration(values.Get(keySlowQuery))
+			if queryLog && conf.QueryLog == nil {
+				conf.QueryLog = NewQueryLog(max(slowQuery, 0))
+			}
 		default:
 			// Anything we don't know, store in the custom fields.
 			conf.Custom[k] = vs
@@ -3,7 +3,9 @@ package rds_test
 import (
 	"github.com/krotscheck/go-rds-driver"
 	. "github.com/smartystreets/goconvey/convey"
+	"reflect"
 	"testing"
+	"time"
 )
 
 func Test_Config(t *testing.T) {
@@ -47,4 +49,101 @@ func Test_Config(t *testing.T) {
 		generatedDSN := conf.ToDSN()
 		So(generatedDSN, ShouldEqual, dsn)
 	})
+
+	Convey("Zero Date Policy", t, func() {
+		dsn := "rds://?resource_arn=resourceARN&secret_arn=secretARN&database=database&aws_region=region&zero_date=null"
+		conf, err := rds.NewConfigFromDSN(dsn)
+		So(err, ShouldBeNil)
+		So(conf.ZeroDate, ShouldEqual, rds.ZeroDateNull)
+
+		conf1, err := rds.NewConfigFromDSN(conf.ToDSN())
+		So(err, ShouldBeNil)
+		So(conf1, ShouldResemble, conf)
+
+		dsn = "rds://?resource_arn=resourceARN&secret_arn=secretARN&database=database&aws_region=region&zero_date=invalid"
+		_, err = rds.NewConfigFromDSN(dsn)
+		So(err, ShouldEqual, rds.ErrInvalidZeroDatePolicy)
+	})
+
+	Convey("Dialect", t, func() {
+		dsn := "rds://?resource_arn=resourceARN&secret_arn=secretARN&database=database&aws_region=region&dialect=postgres"
+		conf, err := rds.NewConfigFromDSN(dsn)
+		So(err, ShouldBeNil)
+		So(conf.Dialect, ShouldEqual, rds.DialectNamePostgres)
+
+		conf1, err := rds.NewConfigFromDSN(conf.ToDSN())
+		So(err, ShouldBeNil)
+		So(conf1, ShouldResemble, conf)
+	})
+
+	Convey("Multiple Statements", t, func() {
+		dsn := "rds://?resource_arn=resourceARN&secret_arn=secretARN&database=database&aws_region=region&split_multi=true&atomic_multi=true&capture_returning=true&dml_result_sets=true"
+		conf, err := rds.NewConfigFromDSN(dsn)
+		So(err, ShouldBeNil)
+		So(conf.AtomicMulti, ShouldBeTrue)
+		So(conf.CaptureReturning, ShouldBeTrue)
+		So(conf.DMLResultSets, ShouldBeTrue)
+
+		conf1, err := rds.NewConfigFromDSN(conf.ToDSN())
+		So(err, ShouldBeNil)
+		So(conf1, ShouldResemble, conf)
+	})
+
+	Convey("Transaction Keepalive", t, func() {
+		dsn := "rds://?resource_arn=resourceARN&secret_arn=secretARN&database=database&aws_region=region&tx_keepalive=1m"
+		conf, err := rds.NewConfigFromDSN(dsn)
+		So(err, ShouldBeNil)
+		So(conf.TxKeepAlive, ShouldEqual, time.Minute)
+
+		conf1, err := rds.NewConfigFromDSN(conf.ToDSN())
+		So(err, ShouldBeNil)
+		So(conf1, ShouldResemble, conf)
+
+		dsn = "rds://?resource_arn=resourceARN&secret_arn=secretARN&database=database&aws_region=region&tx_keepalive=3m"
+		_, err = rds.NewConfigFromDSN(dsn)
+		So(err, ShouldEqual, rds.ErrInvalidTxKeepAlive)
+	})
+
+	Convey("Endpoint URL", t, func() {
+		dsn := "rds://?resource_arn=resourceARN&secret_arn=secretARN&database=database&aws_region=region&endpoint_url=http%3A%2F%2Flocalhost%3A8080"
+		conf, err := rds.NewConfigFromDSN(dsn)
+		So(err, ShouldBeNil)
+		So(conf.EndpointURL, ShouldEqual, "http://localhost:8080")
+
+		conf1, err := rds.NewConfigFromDSN(conf.ToDSN())
+		So(err, ShouldBeNil)
+		So(conf1, ShouldResemble, conf)
+	})
+}
+
+func FuzzNewConfigFromDSN(f *testing.F) {
+	f.Add(rds.NewConfig("resourceARN", "secretARN", "database", "region").ToDSN())
+	f.Add(TestMysqlConfig.ToDSN())
+	f.Add(TestPostgresConfig.ToDSN())
+	f.Add("rds://?aws_region=region&database=database&parse_time=false&resource_arn=resourceARN&secret_arn=secretARN&split_multi=true&x-custom-variable=custom1&x-custom-variable=custom2")
+	f.Add("rds://?resource_arn=resourceARN&dialect=postgres&zero_date=null&commit_timeout=5s&tx_keepalive=1m&query_log=true&slow_query_threshold=1s")
+	f.Add("rds://?commit_timeout=-1s&tx_keepalive=-1m&query_log=true&slow_query_threshold=-1s")
+
+	f.Fuzz(func(t *testing.T, dsn string) {
+		conf, err := rds.NewConfigFromDSN(dsn)
+		if err != nil {
+			return
+		}
+		roundTripped, err := rds.NewConfigFromDSN(conf.ToDSN())
+		if err != nil {
+			t.Fatalf("%q generated from %q: %v", conf.ToDSN(), dsn, err)
+		}
+		if roundTripped.ToDSN() != conf.ToDSN() {
+			t.Fatalf("%q generated from %q generates %q", conf.ToDSN(), dsn, roundTripped.ToDSN())
+		}
+		// Query logs redact with functions, which are never deeply equal.
+		if (conf.QueryLog == nil) != (roundTripped.QueryLog == nil) ||
+			conf.QueryLog != nil && conf.QueryLog.SlowThreshold != roundTripped.QueryLog.SlowThreshold {
+			t.Fatalf("%q generated from %q parses to another query log", conf.ToDSN(), dsn)
+		}
+		conf.QueryLog, roundTripped.QueryLog = nil, nil
+		if !reflect.DeepEqual(conf, roundTripped) {
+			t.Fatalf("%q generated from %q parses to %+v, not %+v", conf.ToDSN(), dsn, roundTripped, conf)
+		}
+	})
 }
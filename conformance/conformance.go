@@ -0,0 +1,596 @@
+// Package conformance tests that a driver.Connector honours the contract database/sql expects of it: the
+// handling of nulls and the types values are scanned into, transactions, the cancellation of contexts,
+// the reuse of pooled connections, and the resetting and validation of their sessions.
+//
+// The suite runs against any connector, such as one of this driver's using the rdstest fake or a real
+// cluster. It creates, and drops once done, a table of its own in the connector's database. Date and
+// time columns are scanned into time.Time, so the driver must be configured to parse them.
+package conformance
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Dialect of the SQL executed by the suite.
+type Dialect struct {
+	// Placeholder of the nth parameter of a statement, counting from 1.
+	Placeholder func(n int) string
+	// AutoIncrement definition of the table's primary key, including its type.
+	AutoIncrement string
+	// Float, Binary, Boolean and Timestamp types of the table's columns.
+	Float     string
+	Binary    string
+	Boolean   string
+	Timestamp string
+	// LastInsertID is supported, rather than returning an error.
+	LastInsertID bool
+}
+
+// MySQL dialect of the suite.
+var MySQL = &Dialect{
+	Placeholder:   func(int) string { return "?" },
+	AutoIncrement: "INTEGER NOT NULL AUTO_INCREMENT PRIMARY KEY",
+	Float:         "DOUBLE",
+	Binary:        "BLOB",
+	Boolean:       "BOOLEAN",
+	Timestamp:     "DATETIME",
+	LastInsertID:  true,
+}
+
+// PostgreSQL dialect of the suite.
+var PostgreSQL = &Dialect{
+	Placeholder:   func(n int) string { return fmt.Sprintf("$%d", n) },
+	AutoIncrement: "SERIAL PRIMARY KEY",
+	Float:         "DOUBLE PRECISION",
+	Binary:        "BYTEA",
+	Boolean:       "BOOLEAN",
+	Timestamp:     "TIMESTAMP",
+}
+
+// Run the suite against the connector, as subtests of t.
+func Run(t *testing.T, connector driver.Connector, dialect *Dialect) {
+	suffix := make([]byte, 4)
+	_, _ = rand.Read(suffix)
+	s := &suite{
+		connector: connector,
+		dialect:   dialect,
+		table:     "conformance_" + hex.EncodeToString(suffix),
+		db:        sql.OpenDB(connector),
+	}
+	defer s.db.Close()
+
+	ctx := context.Background()
+	_, err := s.db.ExecContext(ctx, fmt.Sprintf("CREATE TABLE %s (id %s, name VARCHAR(64), amount INTEGER, ratio %s, enabled %s, payload %s, created %s)",
+		s.table, dialect.AutoIncrement, dialect.Float, dialect.Boolean, dialect.Binary, dialect.Timestamp))
+	if err != nil {
+		t.Fatalf("creating the table: %v", err)
+	}
+	defer func() {
+		if _, err := s.db.ExecContext(ctx, "DROP TABLE "+s.table); err != nil {
+			t.Errorf("dropping the table: %v", err)
+		}
+	}()
+
+	t.Run("Ping", s.testPing)
+	t.Run("Nulls", s.testNulls)
+	t.Run("ScanTypes", s.testScanTypes)
+	t.Run("Parameters", s.testParameters)
+	t.Run("Results", s.testResults)
+	t.Run("Rows", s.testRows)
+	t.Run("PreparedStatements", s.testPreparedStatements)
+	t.Run("Transactions", s.testTransactions)
+	t.Run("ContextCancellation", s.testContextCancellation)
+	t.Run("PoolReuse", s.testPoolReuse)
+	t.Run("ResetSession", s.testResetSession)
+	t.Run("IsValid", s.testIsValid)
+}
+
+// created is the timestamp of the rows inserted by the suite.
+var created = time.Date(2021, 6, 1, 12, 30, 45, 0, time.UTC)
+
+type suite struct {
+	connector driver.Connector
+	dialect   *Dialect
+	table     string
+	db        *sql.DB
+}
+
+// query with its ? placeholders replaced by those of the dialect, and %s by the table.
+func (s *suite) query(query string) string {
+	query = strings.ReplaceAll(query, "%s", s.table)
+	var b strings.Builder
+	n := 0
+	for _, c := range query {
+		if c != '?' {
+			b.WriteRune(c)
+			continue
+		}
+		n++
+		b.WriteString(s.dialect.Placeholder(n))
+	}
+	return b.String()
+}
+
+// reset the table, inserting the given rows of name, amount, ratio, enabled, payload and created.
+func (s *suite) reset(t *testing.T, rows ...[]any) {
+	t.Helper()
+	ctx := context.Background()
+	if _, err := s.db.ExecContext(ctx, s.query("DELETE FROM %s")); err != nil {
+		t.Fatalf("clearing the table: %v", err)
+	}
+	for _, row := range rows {
+		if _, err := s.db.ExecContext(ctx, s.query("INSERT INTO %s (name, amount, ratio, enabled, payload, created) VALUES (?, ?, ?, ?, ?, ?)"), row...); err != nil {
+			t.Fatalf("inserting %v: %v", row, err)
+		}
+	}
+}
+
+// count of the rows in the table.
+func (s *suite) count(t *testing.T, db *sql.DB) int {
+	t.Helper()
+	var count int
+	if err := db.QueryRowContext(context.Background(), s.query("SELECT COUNT(*) FROM %s")).Scan(&count); err != nil {
+		t.Fatalf("counting rows: %v", err)
+	}
+	return count
+}
+
+func (s *suite) testPing(t *testing.T) {
+	if err := s.db.PingContext(context.Background()); err != nil {
+		t.Fatalf("ping: %v", err)
+	}
+}
+
+func (s *suite) testNulls(t *testing.T) {
+	ctx := context.Background()
+	s.reset(t, []any{nil, nil, nil, nil, nil, nil})
+	query := s.query("SELECT name, amount, ratio, enabled, payload, created FROM %s")
+
+	var (
+		name    sql.NullString
+		amount  sql.NullInt64
+		ratio   sql.NullFloat64
+		enabled sql.NullBool
+		payload []byte
+		when    sql.NullTime
+	)
+	if err := s.db.QueryRowContext(ctx, query).Scan(&name, &amount, &ratio, &enabled, &payload, &when); err != nil {
+		t.Fatalf("scanning nulls: %v", err)
+	}
+	if name.Valid || amount.Valid || ratio.Valid || enabled.Valid || payload != nil || when.Valid {
+		t.Errorf("scanned nulls as %v, %v, %v, %v, %v, %v", name, amount, ratio, enabled, payload, when)
+	}
+
+	var (
+		namePtr    *string
+		amountPtr  *int64
+		ratioPtr   *float64
+		enabledPtr *bool
+		payloadAny any
+		whenPtr    *time.Time
+	)
+	if err := s.db.QueryRowContext(ctx, query).Scan(&namePtr, &amountPtr, &ratioPtr, &enabledPtr, &payloadAny, &whenPtr); err != nil {
+		t.Fatalf("scanning nulls into pointers: %v", err)
+	}
+	if namePtr != nil || amountPtr != nil || ratioPtr != nil || enabledPtr != nil || payloadAny != nil || whenPtr != nil {
+		t.Errorf("scanned nulls as %v, %v, %v, %v, %v, %v", namePtr, amountPtr, ratioPtr, enabledPtr, payloadAny, whenPtr)
+	}
+
+	var generic sql.Null[int64]
+	if err := s.db.QueryRowContext(ctx, s.query("SELECT amount FROM %s")).Scan(&generic); err != nil || generic.Valid {
+		t.Errorf("scanned null into sql.Null[int64] as %v, %v", generic, err)
+	}
+	var plain int64
+	if err := s.db.QueryRowContext(ctx, s.query("SELECT amount FROM %s")).Scan(&plain); err == nil {
+		t.Errorf("scanning null into an int64 succeeded")
+	}
+}
+
+func (s *suite) testScanTypes(t *testing.T) {
+	ctx := context.Background()
+	payload := []byte{0x00, 0x01, 0x7f, 0xff}
+	s.reset(t, []any{"alice", 42, 1.5, true, payload, created})
+
+	var (
+		name    string
+		amount  int64
+		ratio   float64
+		enabled bool
+		blob    []byte
+		when    time.Time
+	)
+	err := s.db.QueryRowContext(ctx, s.query("SELECT name, amount, ratio, enabled, payload, created FROM %s")).
+		Scan(&name, &amount, &ratio, &enabled, &blob, &when)
+	switch {
+	case err != nil:
+		t.Fatalf("scanning values: %v", err)
+	case name != "alice", amount != 42, ratio != 1.5, !enabled, string(blob) != string(payload), !when.Equal(created):
+		t.Errorf("scanned %q, %d, %v, %v, %v, %v", name, amount, ratio, enabled, blob, when)
+	}
+
+	// Integers may be scanned into any numeric type, or a string.
+	var (
+		i    int
+		i8   int8
+		i16  int16
+		i32  int32
+		u    uint
+		u8   uint8
+		u16  uint16
+		u32  uint32
+		u64  uint64
+		f32  float32
+		text string
+		raw  any
+	)
+	err = s.db.QueryRowContext(ctx, s.query("SELECT amount, amount, amount, amount, amount, amount, amount, amount, amount, amount, amount, amount FROM %s")).
+		Scan(&i, &i8, &i16, &i32, &u, &u8, &u16, &u32, &u64, &f32, &text, &raw)
+	switch {
+	case err != nil:
+		t.Fatalf("scanning integers: %v", err)
+	case i != 42, i8 != 42, i16 != 42, i32 != 42, u != 42, u8 != 42, u16 != 42, u32 != 42, u64 != 42, f32 != 42, text != "42":
+		t.Errorf("scanned integers as %d, %d, %d, %d, %d, %d, %d, %d, %d, %v, %q", i, i8, i16, i32, u, u8, u16, u32, u64, f32, text)
+	}
+	if v, ok := raw.(int64); !ok || v != 42 {
+		t.Errorf("scanned an integer into any as %T %v", raw, raw)
+	}
+
+	// Floats and strings may be scanned into each other, and strings into bytes.
+	var ratio32 float32
+	var ratioText string
+	var nameBytes []byte
+	var nameAny any
+	err = s.db.QueryRowContext(ctx, s.query("SELECT ratio, ratio, name, name FROM %s")).Scan(&ratio32, &ratioText, &nameBytes, &nameAny)
+	switch {
+	case err != nil:
+		t.Fatalf("scanning floats and strings: %v", err)
+	case ratio32 != 1.5, ratioText != "1.5", string(nameBytes) != "alice":
+		t.Errorf("scanned %v, %q, %q", ratio32, ratioText, nameBytes)
+	}
+	switch v := nameAny.(type) {
+	case string:
+		if v != "alice" {
+			t.Errorf("scanned a string into any as %q", v)
+		}
+	case []byte:
+		if string(v) != "alice" {
+			t.Errorf("scanned a string into any as %q", v)
+		}
+	default:
+		t.Errorf("scanned a string into any as %T", nameAny)
+	}
+
+	// Raw bytes are only valid until the next call to Next.
+	rows, err := s.db.QueryContext(ctx, s.query("SELECT name FROM %s"))
+	if err != nil {
+		t.Fatalf("querying: %v", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var rawName sql.RawBytes
+		if err := rows.Scan(&rawName); err != nil || string(rawName) != "alice" {
+			t.Errorf("scanned sql.RawBytes as %q, %v", rawName, err)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		t.Errorf("iterating rows: %v", err)
+	}
+}
+
+func (s *suite) testParameters(t *testing.T) {
+	ctx := context.Background()
+	s.reset(t,
+		[]any{sql.NullString{String: "int", Valid: true}, int(1), float32(0.5), true, []byte("int"), created},
+		[]any{"int32", int32(2), float64(0.25), false, []byte{}, created.Add(time.Hour)},
+		[]any{"int64", int64(3), nil, nil, nil, nil},
+	)
+
+	var count int
+	err := s.db.QueryRowContext(ctx, s.query("SELECT COUNT(*) FROM %s WHERE name = ? AND amount = ? AND ratio = ? AND enabled = ?"), "int", 1, 0.5, true).Scan(&count)
+	if err != nil || count != 1 {
+		t.Errorf("matched %d rows by their parameters, %v", count, err)
+	}
+	err = s.db.QueryRowContext(ctx, s.query("SELECT COUNT(*) FROM %s WHERE created > ?"), created).Scan(&count)
+	if err != nil || count != 1 {
+		t.Errorf("matched %d rows by a time, %v", count, err)
+	}
+	var amount int64
+	err = s.db.QueryRowContext(ctx, s.query("SELECT amount FROM %s WHERE name = ?"), "int64").Scan(&amount)
+	if err != nil || amount != 3 {
+		t.Errorf("selected amount %d, %v", amount, err)
+	}
+	var payload []byte
+	err = s.db.QueryRowContext(ctx, s.query("SELECT payload FROM %s WHERE name = ?"), "int").Scan(&payload)
+	if err != nil || string(payload) != "int" {
+		t.Errorf("selected payload %q, %v", payload, err)
+	}
+}
+
+func (s *suite) testResults(t *testing.T) {
+	ctx := context.Background()
+	s.reset(t, []any{"a", 1, nil, nil, nil, nil}, []any{"b", 1, nil, nil, nil, nil})
+
+	result, err := s.db.ExecContext(ctx, s.query("UPDATE %s SET amount = ? WHERE amount = ?"), 2, 1)
+	if err != nil {
+		t.Fatalf("updating: %v", err)
+	}
+	if affected, err := result.RowsAffected(); err != nil || affected != 2 {
+		t.Errorf("updated %d rows, %v", affected, err)
+	}
+
+	result, err = s.db.ExecContext(ctx, s.query("INSERT INTO %s (name) VALUES (?)"), "c")
+	if err != nil {
+		t.Fatalf("inserting: %v", err)
+	}
+	id, err := result.LastInsertId()
+	switch {
+	case !s.dialect.LastInsertID && err == nil:
+		t.Errorf("returned the last insert ID %d, which isn't supported", id)
+	case s.dialect.LastInsertID && err != nil:
+		t.Errorf("returning the last insert ID: %v", err)
+	case s.dialect.LastInsertID:
+		var name string
+		if err := s.db.QueryRowContext(ctx, s.query("SELECT name FROM %s WHERE id = ?"), id).Scan(&name); err != nil || name != "c" {
+			t.Errorf("selected %q by the last insert ID %d, %v", name, id, err)
+		}
+	}
+
+	result, err = s.db.ExecContext(ctx, s.query("DELETE FROM %s WHERE name = ?"), "missing")
+	if err != nil {
+		t.Fatalf("deleting: %v", err)
+	}
+	if affected, err := result.RowsAffected(); err != nil || affected != 0 {
+		t.Errorf("deleted %d rows, %v", affected, err)
+	}
+}
+
+func (s *suite) testRows(t *testing.T) {
+	ctx := context.Background()
+	s.reset(t, []any{"b", 2, nil, nil, nil, nil}, []any{"c", 3, nil, nil, nil, nil}, []any{"a", 1, nil, nil, nil, nil})
+
+	rows, err := s.db.QueryContext(ctx, s.query("SELECT name, amount FROM %s ORDER BY amount"))
+	if err != nil {
+		t.Fatalf("querying: %v", err)
+	}
+	defer rows.Close()
+	columns, err := rows.Columns()
+	if err != nil || len(columns) != 2 || !strings.EqualFold(columns[0], "name") || !strings.EqualFold(columns[1], "amount") {
+		t.Errorf("returned columns %v, %v", columns, err)
+	}
+	types, err := rows.ColumnTypes()
+	if err != nil || len(types) != 2 {
+		t.Errorf("returned column types %v, %v", types, err)
+	}
+	var names []string
+	for rows.Next() {
+		var name string
+		var amount int
+		if err := rows.Scan(&name, &amount); err != nil {
+			t.Fatalf("scanning: %v", err)
+		}
+		names = append(names, name)
+	}
+	if err := rows.Err(); err != nil {
+		t.Errorf("iterating rows: %v", err)
+	}
+	if strings.Join(names, ",") != "a,b,c" {
+		t.Errorf("returned rows %v", names)
+	}
+	if err := rows.Close(); err != nil {
+		t.Errorf("closing rows: %v", err)
+	}
+
+	var name string
+	if err := s.db.QueryRowContext(ctx, s.query("SELECT name FROM %s WHERE amount = ?"), 4).Scan(&name); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("scanning a missing row returned %v", err)
+	}
+}
+
+func (s *suite) testPreparedStatements(t *testing.T) {
+	ctx := context.Background()
+	s.reset(t)
+
+	stmt, err := s.db.PrepareContext(ctx, s.query("INSERT INTO %s (name, amount) VALUES (?, ?)"))
+	if err != nil {
+		t.Fatalf("preparing: %v", err)
+	}
+	for i := 1; i <= 3; i++ {
+		if _, err := stmt.ExecContext(ctx, fmt.Sprintf("row %d", i), i); err != nil {
+			t.Errorf("executing the statement: %v", err)
+		}
+	}
+	if err := stmt.Close(); err != nil {
+		t.Errorf("closing the statement: %v", err)
+	}
+	if count := s.count(t, s.db); count != 3 {
+		t.Errorf("inserted %d rows", count)
+	}
+}
+
+func (s *suite) testTransactions(t *testing.T) {
+	ctx := context.Background()
+	s.reset(t)
+	insert := s.query("INSERT INTO %s (name) VALUES (?)")
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatalf("beginning: %v", err)
+	}
+	if _, err := tx.ExecContext(ctx, insert, "committed"); err != nil {
+		t.Fatalf("inserting: %v", err)
+	}
+	var count int
+	if err := tx.QueryRowContext(ctx, s.query("SELECT COUNT(*) FROM %s")).Scan(&count); err != nil || count != 1 {
+		t.Errorf("the transaction saw %d of its own rows, %v", count, err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("committing: %v", err)
+	}
+	if err := tx.Commit(); !errors.Is(err, sql.ErrTxDone) {
+		t.Errorf("committing twice returned %v", err)
+	}
+	if count := s.count(t, s.db); count != 1 {
+		t.Errorf("committed %d rows", count)
+	}
+
+	tx, err = s.db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatalf("beginning: %v", err)
+	}
+	if _, err := tx.ExecContext(ctx, insert, "rolled back"); err != nil {
+		t.Fatalf("inserting: %v", err)
+	}
+	if err := tx.Rollback(); err != nil {
+		t.Fatalf("rolling back: %v", err)
+	}
+	if err := tx.Rollback(); !errors.Is(err, sql.ErrTxDone) {
+		t.Errorf("rolling back twice returned %v", err)
+	}
+	if count := s.count(t, s.db); count != 1 {
+		t.Errorf("rolled back to %d rows", count)
+	}
+}
+
+func (s *suite) testContextCancellation(t *testing.T) {
+	s.reset(t)
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := s.db.QueryContext(cancelled, s.query("SELECT name FROM %s")); !errors.Is(err, context.Canceled) {
+		t.Errorf("querying with a cancelled context returned %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatalf("beginning: %v", err)
+	}
+	if _, err := tx.ExecContext(ctx, s.query("INSERT INTO %s (name) VALUES (?)"), "cancelled"); err != nil {
+		t.Fatalf("inserting: %v", err)
+	}
+	cancel()
+	if err := tx.Commit(); err == nil {
+		t.Errorf("committed a transaction whose context was cancelled")
+	}
+	if count := s.count(t, s.db); count != 0 {
+		t.Errorf("the cancelled transaction inserted %d rows", count)
+	}
+}
+
+func (s *suite) testPoolReuse(t *testing.T) {
+	ctx := context.Background()
+	s.reset(t, []any{"pooled", 1, nil, nil, nil, nil})
+	db := sql.OpenDB(s.connector)
+	defer db.Close()
+
+	// Connections are returned to the pool once their rows are closed.
+	for i := 0; i < 10; i++ {
+		s.count(t, db)
+	}
+	if open := db.Stats().OpenConnections; open != 1 {
+		t.Errorf("sequential queries opened %d connections", open)
+	}
+
+	db.SetMaxOpenConns(2)
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var name string
+			errs <- db.QueryRowContext(ctx, s.query("SELECT name FROM %s")).Scan(&name)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Errorf("concurrent query: %v", err)
+		}
+	}
+	if open := db.Stats().OpenConnections; open > 2 {
+		t.Errorf("concurrent queries opened %d connections", open)
+	}
+}
+
+func (s *suite) testResetSession(t *testing.T) {
+	ctx := context.Background()
+	s.reset(t)
+	db := sql.OpenDB(s.connector)
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatalf("taking a connection: %v", err)
+	}
+	// Leave a transaction open on the connection, which database/sql knows nothing of.
+	err = conn.Raw(func(driverConn any) error {
+		resetter, ok := driverConn.(driver.SessionResetter)
+		if !ok {
+			t.Skip("the driver doesn't reset sessions")
+		}
+		if err := resetter.ResetSession(ctx); err != nil {
+			return fmt.Errorf("resetting a clean session: %w", err)
+		}
+		beginner, ok := driverConn.(driver.ConnBeginTx)
+		if !ok {
+			t.Skip("the driver doesn't begin transactions with options")
+		}
+		if _, err := beginner.BeginTx(ctx, driver.TxOptions{}); err != nil {
+			return fmt.Errorf("beginning: %w", err)
+		}
+		execer, ok := driverConn.(driver.ExecerContext)
+		if !ok {
+			t.Skip("the driver doesn't execute statements without preparing them")
+		}
+		_, err := execer.ExecContext(ctx, s.query("INSERT INTO %s (name) VALUES (?)"), []driver.NamedValue{{Ordinal: 1, Value: "abandoned"}})
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.Close(); err != nil {
+		t.Fatalf("returning the connection: %v", err)
+	}
+
+	// The next use of the connection resets its session, so the transaction is never committed.
+	if count := s.count(t, db); count != 0 {
+		t.Errorf("the abandoned transaction inserted %d rows", count)
+	}
+	if count := s.count(t, s.db); count != 0 {
+		t.Errorf("the abandoned transaction inserted %d rows", count)
+	}
+}
+
+func (s *suite) testIsValid(t *testing.T) {
+	conn, err := s.connector.Connect(context.Background())
+	if err != nil {
+		t.Fatalf("connecting: %v", err)
+	}
+	validator, ok := conn.(driver.Validator)
+	if !ok {
+		_ = conn.Close()
+		t.Skip("the driver doesn't validate connections")
+	}
+	if !validator.IsValid() {
+		t.Errorf("a new connection isn't valid")
+	}
+	if err := conn.Close(); err != nil {
+		t.Errorf("closing: %v", err)
+	}
+	if validator.IsValid() {
+		t.Errorf("a closed connection is valid")
+	}
+}
@@ -0,0 +1,58 @@
+package rds_test
+
+import (
+	"testing"
+
+	"github.com/krotscheck/go-rds-driver"
+	"github.com/krotscheck/go-rds-driver/conformance"
+	"github.com/krotscheck/go-rds-driver/rdstest"
+)
+
+func Test_Conformance(t *testing.T) {
+	// SQLite's auto-incrementing keys differ from those of either database.
+	sqlite := func(dialect *conformance.Dialect) *conformance.Dialect {
+		fake := *dialect
+		fake.AutoIncrement = "INTEGER PRIMARY KEY AUTOINCREMENT"
+		return &fake
+	}
+
+	t.Run("Fake MySQL", func(t *testing.T) {
+		fake := rdstest.NewFake()
+		defer fake.Close()
+		conf := rds.NewConfig("resourceARN", "secretARN", "database", "region")
+		conf.ParseTime = true
+		conformance.Run(t, rds.NewConnector(rds.NewDriver(), fake, conf), sqlite(conformance.MySQL))
+	})
+
+	t.Run("Fake PostgreSQL", func(t *testing.T) {
+		fake := rdstest.NewFake(rdstest.WithVersion("PostgreSQL 13.9"))
+		defer fake.Close()
+		conf := rds.NewConfig("resourceARN", "secretARN", "database", "region")
+		conf.ParseTime = true
+		conformance.Run(t, rds.NewConnector(rds.NewDriver(), fake, conf), sqlite(conformance.PostgreSQL))
+	})
+
+	clusters := []struct {
+		name    string
+		conf    *rds.Config
+		dialect *conformance.Dialect
+	}{
+		{"MySQL", TestMysqlConfig, conformance.MySQL},
+		{"MySQL 8", TestMysql8Config, conformance.MySQL},
+		{"PostgreSQL", TestPostgresConfig, conformance.PostgreSQL},
+	}
+	for _, cluster := range clusters {
+		t.Run(cluster.name, func(t *testing.T) {
+			if cluster.conf.ResourceArn == "" {
+				t.Skip("no cluster configured")
+			}
+			conf := *cluster.conf
+			conf.ParseTime = true
+			connector, err := rds.NewDriver().OpenConnector(conf.ToDSN())
+			if err != nil {
+				t.Fatal(err)
+			}
+			conformance.Run(t, connector, cluster.dialect)
+		})
+	}
+}
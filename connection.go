@@ -5,7 +5,10 @@ import (
 	"database/sql"
 	"database/sql/driver"
 	"fmt"
+	"log/slog"
 	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/rdsdata"
@@ -20,38 +23,57 @@ var _ driver.Pinger = (*Connection)(nil)             // explicit compile time ty
 var _ driver.QueryerContext = (*Connection)(nil)     // explicit compile time type check
 var _ driver.SessionResetter = (*Connection)(nil)    // explicit compile time type check
 var _ driver.Validator = (*Connection)(nil)          // explicit compile time type check
-// var _ driver.NamedValueChecker = (*Connection)(nil)  // explicit compile time type check
+var _ driver.NamedValueChecker = (*Connection)(nil)  // explicit compile time type check
 
 // NewConnection that can make transaction and statement requests against RDS
-func NewConnection(ctx context.Context, rds AWSClientInterface, conf *Config, dialect Dialect) driver.Conn {
+func NewConnection(_ context.Context, rds AWSClientInterface, conf *Config, dialect Dialect) driver.Conn {
 	return &Connection{
-		ctx:         ctx,
-		rds:         rds,
-		resourceARN: conf.ResourceArn,
-		secretARN:   conf.SecretArn,
-		database:    conf.Database,
-		splitMulti:  conf.SplitMulti,
-		closed:      false,
-		dialect:     dialect,
+		rds:              rds,
+		resourceARN:      conf.ResourceArn,
+		secretARN:        conf.SecretArn,
+		database:         conf.Database,
+		splitMulti:       conf.SplitMulti,
+		atomicMulti:      conf.AtomicMulti,
+		captureReturning: conf.CaptureReturning,
+		dmlResultSets:    conf.DMLResultSets,
+		log:              newEventLogger(conf),
+		hooks:            hooksOrNoop(conf.Hooks),
+		metrics:          metricsOrDefault(conf.Metrics),
+		queryLog:         conf.QueryLog,
+		closed:           false,
+		dialect:          dialect,
+		converters:       conf.Converters,
+		commitTimeout:    conf.CommitTimeout,
+		txKeepAlive:      conf.TxKeepAlive,
 	}
 }
 
 // Connection to RDS's Aurora Serverless Data API
 type Connection struct {
-	ctx         context.Context
-	rds         AWSClientInterface
-	resourceARN string
-	secretARN   string
-	database    string
-	splitMulti  bool
-	tx          *Tx // The current transaction, if set
-	closed      bool
-	dialect     Dialect
+	rds              AWSClientInterface
+	resourceARN      string
+	secretARN        string
+	database         string
+	splitMulti       bool
+	atomicMulti      bool
+	captureReturning bool
+	dmlResultSets    bool
+	log              *eventLogger
+	hooks            Hooks
+	metrics          Metrics
+	queryLog         *QueryLog
+	tx               *Tx // The current transaction, if set
+	closed           bool
+	bad              atomic.Bool // Set once the connection can't be reused, such as after a transaction expires
+	dialect          Dialect
+	converters       *ConverterRegistry
+	commitTimeout    time.Duration
+	txKeepAlive      time.Duration
 }
 
 // Ping the database
 func (r *Connection) Ping(ctx context.Context) (err error) {
-	_, err = r.rds.ExecuteStatement(ctx, &rdsdata.ExecuteStatementInput{
+	_, err = r.executeStatement(ctx, &rdsdata.ExecuteStatementInput{
 		ResourceArn: &r.resourceARN,
 		Database:    &r.database,
 		SecretArn:   &r.secretARN,
@@ -61,6 +83,23 @@ func (r *Connection) Ping(ctx context.Context) (err error) {
 	return
 }
 
+// CheckNamedValue passes arguments with a registered ParameterEncoder through unchanged, deferring
+// all others to the default database/sql conversion.
+func (r *Connection) CheckNamedValue(nv *driver.NamedValue) error {
+	if _, ok := lookupParameterEncoder(r.converters, nv.Value); ok {
+		return nil
+	}
+	return driver.ErrSkip
+}
+
+// CurrentTx returns the transaction currently open on this connection.
+func (r *Connection) CurrentTx() (*Tx, error) {
+	if r.tx == nil {
+		return nil, ErrNoTransaction
+	}
+	return r.tx, nil
+}
+
 // Prepare returns a prepared statement, bound to this connection.
 func (r *Connection) Prepare(query string) (driver.Stmt, error) {
 	return r.PrepareContext(context.Background(), query)
@@ -70,8 +109,7 @@ func (r *Connection) Prepare(query string) (driver.Stmt, error) {
 func (r *Connection) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
 	queries := []string{query}
 	if r.splitMulti {
-		queries = strings.Split(query, ";")
-		queries = removeEmptyQueries(queries)
+		queries = removeEmptyQueries(syntaxOf(r.dialect).split(query))
 	}
 	return NewStatement(ctx, r, queries), nil
 }
@@ -81,43 +119,61 @@ func (r *Connection) Close() error {
 	if r.closed {
 		return ErrClosed
 	}
-	if r.tx != nil {
-		if err := r.tx.Rollback(); err != nil {
-			return err
+	var err error
+	if tx := r.tx; tx != nil {
+		if !r.bad.Load() {
+			err = tx.Rollback()
 		}
+		tx.abandon()
 	}
 	r.rds = nil
 	r.closed = true
-	return nil
+	return err
 }
 
 // Begin starts and returns a new transaction.
 func (r *Connection) Begin() (driver.Tx, error) {
-	return r.BeginTx(r.ctx, driver.TxOptions{
+	return r.BeginTx(context.Background(), driver.TxOptions{
 		Isolation: driver.IsolationLevel(sql.LevelDefault),
 		ReadOnly:  false,
 	})
 }
 
-// BeginTx starts and returns a new transaction.
+// BeginTx starts and returns a new transaction. If a transaction is already open on this
+// connection, a nested transaction backed by a savepoint is returned instead.
 func (r *Connection) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if err := r.rollbackCancelled(); err != nil {
+		return nil, err
+	}
+	if r.tx != nil {
+		if sql.IsolationLevel(opts.Isolation) != sql.LevelDefault || opts.ReadOnly {
+			return nil, fmt.Errorf("nested transactions inherit the options of the enclosing transaction")
+		}
+		return r.tx.Begin(ctx)
+	}
 	if !r.dialect.IsIsolationLevelSupported(opts.Isolation) {
 		return nil, fmt.Errorf("isolation level %d not supported", opts.Isolation)
 	}
 
-	output, err := r.rds.BeginTransaction(ctx, &rdsdata.BeginTransactionInput{
+	event := r.transactionEvent(TransactionBegin, "")
+	hookCtx := r.hooks.BeforeTransaction(ctx, event)
+	start := time.Now()
+	output, err := r.rds.BeginTransaction(hookCtx, &rdsdata.BeginTransactionInput{
 		Database:    aws.String(r.database),
 		ResourceArn: aws.String(r.resourceARN),
 		SecretArn:   aws.String(r.secretARN),
 	})
+	r.metrics.ObserveCall(CallBeginTransaction, time.Since(start), err)
+	if output != nil {
+		event.TransactionID = aws.ToString(output.TransactionId)
+	}
+	r.hooks.AfterTransaction(hookCtx, event, err)
 	if err != nil {
 		return nil, err
 	}
-	r.tx = &Tx{
-		Done:          false,
-		TransactionID: output.TransactionId,
-		conn:          r,
-	}
+	r.tx = newTx(ctx, output.TransactionId, r)
+	r.log.transaction(ctx, "rds: transaction begun",
+		slog.String("transaction_id", aws.ToString(output.TransactionId)))
 
 	query := r.dialect.GetTransactionSetupQuery(opts)
 	if query != "" {
@@ -136,9 +192,15 @@ func (r *Connection) BeginTx(ctx context.Context, opts driver.TxOptions) (driver
 // if the connection has been used before. If the driver returns ErrBadConn
 // the connection is discarded.
 func (r *Connection) ResetSession(_ context.Context) error {
-	if r.tx != nil {
-		if err := r.tx.Rollback(); err != nil {
-			return err
+	if r.bad.Load() {
+		// Don't attempt to roll back an expired transaction
+		return driver.ErrBadConn
+	}
+	if tx := r.tx; tx != nil {
+		// Should the rollback fail, the connection must still be discarded, lest the next statement is
+		// executed within the transaction left behind. Closing it attempts the rollback once more.
+		if err := tx.Rollback(); err != nil {
+			tx.stopKeepAlive()
 		}
 		return driver.ErrBadConn
 	}
@@ -147,7 +209,7 @@ func (r *Connection) ResetSession(_ context.Context) error {
 
 // IsValid is called prior to placing the connection into the connection pool. The connection will be discarded if false is returned.
 func (r *Connection) IsValid() bool {
-	if r.closed {
+	if r.closed || r.bad.Load() {
 		return false
 	}
 	if r.database == "" {
@@ -196,3 +258,18 @@ func removeEmptyQueries(s []string) []string {
 	}
 	return r
 }
+
+// rollbackCancelled transaction, whose context was cancelled, before the connection is used again. Should
+// the rollback fail, the connection is discarded rather than used within the transaction left behind.
+func (r *Connection) rollbackCancelled() error {
+	if r.tx == nil || r.tx.ctx.Err() == nil {
+		return nil
+	}
+	_ = r.tx.Rollback()
+	if r.tx != nil {
+		r.bad.Store(true)
+		r.tx.stopKeepAlive()
+		return driver.ErrBadConn
+	}
+	return nil
+}
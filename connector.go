@@ -7,8 +7,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/rdsdata"
 	"github.com/aws/aws-sdk-go-v2/service/rdsdata/types"
-	"log"
-	"strings"
+	"log/slog"
 	"time"
 )
 
@@ -17,9 +16,11 @@ var _ driver.Connector = (*Connector)(nil) // explicit compile time type check
 // NewConnector from the provided configuration fields
 func NewConnector(d driver.Driver, client AWSClientInterface, conf *Config) *Connector {
 	return &Connector{
-		driver: d,
-		rds:    client,
-		conf:   conf,
+		driver:  d,
+		rds:     client,
+		conf:    conf,
+		log:     newEventLogger(conf),
+		metrics: metricsOrDefault(conf.Metrics),
 	}
 }
 
@@ -30,10 +31,15 @@ type Connector struct {
 	conf                 *Config
 	lastSuccessfulWakeup time.Time
 	dialect              Dialect
+	log                  *eventLogger
+	metrics              Metrics
 }
 
 // Connect returns a connection to the database.
 func (r *Connector) Connect(ctx context.Context) (driver.Conn, error) {
+	if r.conf.TxKeepAlive >= TransactionIdleTimeout {
+		return nil, ErrInvalidTxKeepAlive
+	}
 	if r.lastSuccessfulWakeup.Add(time.Minute * 5).Before(time.Now()) {
 		dialect, err := r.Wakeup()
 		if err != nil {
@@ -46,6 +52,11 @@ func (r *Connector) Connect(ctx context.Context) (driver.Conn, error) {
 	return NewConnection(ctx, r.rds, r.conf, r.dialect), nil
 }
 
+// Metrics the connector, and its connections, report their usage of the Data API to.
+func (r *Connector) Metrics() Metrics {
+	return r.metrics
+}
+
 // Driver returns the underlying Driver of the Connector, mainly to maintain compatibility with the Driver method on sql.DB.
 func (r *Connector) Driver() driver.Driver {
 	return r.driver
@@ -61,8 +72,33 @@ func (r *Connector) Wakeup() (dialect Dialect, err error) {
 		Parameters:  []types.SqlParameter{},
 	}
 
+	// A dialect forced by configuration skips detection, but the cluster must still be woken up.
+	var factory DialectFactory
+	if r.conf.Dialect != "" {
+		var ok bool
+		if factory, ok = lookupDialect(r.conf.Dialect); !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownDialect, r.conf.Dialect)
+		}
+	}
+
+	hooks := hooksOrNoop(r.conf.Hooks)
+	event := &WakeupEvent{
+		ResourceArn: r.conf.ResourceArn,
+		Database:    r.conf.Database,
+	}
+	ctx := hooks.BeforeWakeup(context.TODO(), event)
+	start := time.Now()
+	defer func() {
+		event.Dialect = dialect
+		hooks.AfterWakeup(ctx, event, err)
+		r.metrics.ObserveWakeup(time.Since(start), event.Attempts, err)
+	}()
+
 	err = r.retry(10, time.Second, func() error {
-		out, err := r.rds.ExecuteStatement(context.TODO(), request)
+		event.Attempts++
+		callStart := time.Now()
+		out, err := r.rds.ExecuteStatement(ctx, request)
+		r.metrics.ObserveCall(CallExecuteStatement, time.Since(callStart), err)
 
 		if err != nil {
 			return err
@@ -78,14 +114,25 @@ func (r *Connector) Wakeup() (dialect Dialect, err error) {
 			return fmt.Errorf("invalid response to version request")
 		}
 
+		if factory != nil {
+			dialect = factory(r.conf)
+			r.log.wakeup(context.Background(), "rds: cluster awake",
+				slog.String("dialect", r.conf.Dialect))
+			return nil
+		}
+
 		field := row[0]
 		version := field.(*types.FieldMemberStringValue).Value
+		event.Version = version
 
-		if strings.Contains(strings.ToLower(version), "postgres") {
-			dialect = NewPostgres(r.conf)
-		} else {
-			dialect = NewMySQL(r.conf)
+		detected, ok := detectDialect(version)
+		if !ok {
+			return fmt.Errorf("no dialect found for version %s", version)
 		}
+		dialect = detected(r.conf)
+		r.log.wakeup(context.Background(), "rds: cluster awake",
+			slog.String("version", version),
+			slog.String("dialect", fmt.Sprintf("%T", dialect)))
 
 		return err
 	})
@@ -105,7 +152,11 @@ func (r *Connector) retry(attempts int, sleep time.Duration, callback func() err
 		}
 
 		time.Sleep(sleep)
-		log.Println("retrying after error:", err)
+		r.metrics.ObserveRetry(err)
+		r.log.retry(context.Background(), "rds: retrying wakeup after error",
+			slog.Int("attempt", i+1),
+			slog.Int("max_attempts", attempts),
+			slog.Any("error", err))
 	}
 	return fmt.Errorf("after %d attempts, last error: %s", attempts, err)
 }
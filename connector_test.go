@@ -2,6 +2,7 @@ package rds_test
 
 import (
 	"context"
+	"errors"
 	"github.com/golang/mock/gomock"
 	"github.com/krotscheck/go-rds-driver"
 	. "github.com/smartystreets/goconvey/convey"
@@ -26,8 +27,81 @@ func Test_Connector(t *testing.T) {
 			So(connection, ShouldNotBeNil)
 		})
 
+		Convey("Rejects a keepalive the Data API's idle timeout would precede", func() {
+			keepAliveConf := *conf
+			keepAliveConf.TxKeepAlive = rds.TransactionIdleTimeout
+			_, err := rds.NewConnector(d, mockRDS, &keepAliveConf).Connect(ctx)
+			So(err, ShouldEqual, rds.ErrInvalidTxKeepAlive)
+		})
+
 		Convey("Driver", func() {
 			So(connector.Driver(), ShouldEqual, d)
 		})
+
+		Convey("Wakeup", func() {
+			ExpectWakeup(mockRDS, conf)
+
+			Convey("Detects the dialect", func() {
+				dialect, err := connector.Wakeup()
+				So(err, ShouldBeNil)
+				So(dialect, ShouldHaveSameTypeAs, &rds.DialectMySQL{})
+			})
+
+			Convey("Detects registered dialects", func() {
+				detected := false
+				rds.RegisterDialect("test_detected", func(version string) bool {
+					return version == "5.7.0"
+				}, func(config *rds.Config) rds.Dialect {
+					detected = true
+					return rds.NewMySQL(config)
+				})
+				defer rds.UnregisterDialect("test_detected")
+
+				_, err := connector.Wakeup()
+				So(err, ShouldBeNil)
+				So(detected, ShouldBeTrue)
+			})
+
+			Convey("Keeps the order of replaced dialects", func() {
+				replaced := false
+				rds.RegisterDialect(rds.DialectNameMySQL, func(version string) bool {
+					return true
+				}, func(config *rds.Config) rds.Dialect {
+					replaced = true
+					return rds.NewMySQL(config)
+				})
+				defer rds.RegisterDialect(rds.DialectNameMySQL, func(version string) bool {
+					return true
+				}, rds.NewMySQL)
+
+				_, err := connector.Wakeup()
+				So(err, ShouldBeNil)
+				So(replaced, ShouldBeTrue)
+
+				// Postgres, registered after MySQL, is still detected first.
+				detected, ok := rds.DetectDialect("PostgreSQL 10.14", conf)
+				So(ok, ShouldBeTrue)
+				So(detected, ShouldHaveSameTypeAs, &rds.DialectPostgres{})
+			})
+
+			Convey("Forced dialect", func() {
+				forcedConf := rds.NewConfig("resourceARN", "secretARN", "database", "region")
+				forcedConf.Dialect = rds.DialectNamePostgres
+				forced := rds.NewConnector(d, mockRDS, forcedConf)
+
+				dialect, err := forced.Wakeup()
+				So(err, ShouldBeNil)
+				So(dialect, ShouldHaveSameTypeAs, &rds.DialectPostgres{})
+			})
+
+			Convey("Unknown dialect", func() {
+				unknownConf := rds.NewConfig("resourceARN", "secretARN", "database", "region")
+				unknownConf.Dialect = "unknown"
+				unknown := rds.NewConnector(d, mockRDS, unknownConf)
+
+				_, err := unknown.Wakeup()
+				So(errors.Is(err, rds.ErrUnknownDialect), ShouldBeTrue)
+			})
+		})
 	})
 }
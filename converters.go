@@ -0,0 +1,136 @@
+package rds
+
+import (
+	"database/sql/driver"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rdsdata/types"
+)
+
+// ColumnMatcher reports whether a registered FieldConverter applies to the given column.
+type ColumnMatcher func(column types.ColumnMetadata) bool
+
+// ParameterEncoder converts a query argument into an RDS SqlParameter.
+type ParameterEncoder func(arg driver.NamedValue) (types.SqlParameter, error)
+
+// DefaultConverters are consulted by every connection, after those registered on its Config.
+var DefaultConverters = NewConverterRegistry()
+
+// RegisterFieldConverter for a column type name in the DefaultConverters registry.
+func RegisterFieldConverter(typeName string, converter FieldConverter) {
+	DefaultConverters.RegisterFieldConverter(typeName, converter)
+}
+
+// RegisterFieldConverterFunc for all columns matched by the passed ColumnMatcher in the DefaultConverters registry.
+func RegisterFieldConverterFunc(matcher ColumnMatcher, converter FieldConverter) {
+	DefaultConverters.RegisterFieldConverterFunc(matcher, converter)
+}
+
+// RegisterParameterEncoder for arguments of the passed type in the DefaultConverters registry.
+func RegisterParameterEncoder(t reflect.Type, encoder ParameterEncoder) {
+	DefaultConverters.RegisterParameterEncoder(t, encoder)
+}
+
+// NewConverterRegistry creates an empty registry.
+func NewConverterRegistry() *ConverterRegistry {
+	return &ConverterRegistry{
+		encoders: map[reflect.Type]ParameterEncoder{},
+	}
+}
+
+type registeredConverter struct {
+	matcher   ColumnMatcher
+	converter FieldConverter
+}
+
+// ConverterRegistry holds user supplied FieldConverters and ParameterEncoders, which take
+// priority over those built into the dialects. A nil registry is empty.
+type ConverterRegistry struct {
+	mu         sync.RWMutex
+	converters []registeredConverter
+	encoders   map[reflect.Type]ParameterEncoder
+}
+
+// RegisterFieldConverter for a column type name, such as a custom postgres domain or enum type.
+func (r *ConverterRegistry) RegisterFieldConverter(typeName string, converter FieldConverter) {
+	r.RegisterFieldConverterFunc(func(column types.ColumnMetadata) bool {
+		return strings.EqualFold(aws.ToString(column.TypeName), typeName)
+	}, converter)
+}
+
+// RegisterFieldConverterFunc for all columns matched by the passed ColumnMatcher. Converters
+// registered later take priority over those registered earlier.
+func (r *ConverterRegistry) RegisterFieldConverterFunc(matcher ColumnMatcher, converter FieldConverter) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.converters = append(r.converters, registeredConverter{matcher: matcher, converter: converter})
+}
+
+// RegisterParameterEncoder for arguments of the passed type.
+func (r *ConverterRegistry) RegisterParameterEncoder(t reflect.Type, encoder ParameterEncoder) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.encoders[t] = encoder
+}
+
+// GetFieldConverter registered for the column, if any.
+func (r *ConverterRegistry) GetFieldConverter(column types.ColumnMetadata) (FieldConverter, bool) {
+	if r == nil {
+		return nil, false
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for i := len(r.converters) - 1; i >= 0; i-- {
+		if r.converters[i].matcher(column) {
+			return r.converters[i].converter, true
+		}
+	}
+	return nil, false
+}
+
+// GetParameterEncoder registered for the type of the passed value, if any.
+func (r *ConverterRegistry) GetParameterEncoder(value interface{}) (ParameterEncoder, bool) {
+	if r == nil || value == nil {
+		return nil, false
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	encoder, ok := r.encoders[reflect.TypeOf(value)]
+	return encoder, ok
+}
+
+// lookupFieldConverter in the configured registry, then the DefaultConverters.
+func lookupFieldConverter(registry *ConverterRegistry, column types.ColumnMetadata) (FieldConverter, bool) {
+	if converter, ok := registry.GetFieldConverter(column); ok {
+		return converter, true
+	}
+	return DefaultConverters.GetFieldConverter(column)
+}
+
+// lookupParameterEncoder in the configured registry, then the DefaultConverters.
+func lookupParameterEncoder(registry *ConverterRegistry, value interface{}) (ParameterEncoder, bool) {
+	if encoder, ok := registry.GetParameterEncoder(value); ok {
+		return encoder, true
+	}
+	return DefaultConverters.GetParameterEncoder(value)
+}
+
+// convertNamedValuesWith the registered ParameterEncoders, falling back to ConvertNamedValue.
+func convertNamedValuesWith(registry *ConverterRegistry, args []driver.NamedValue) ([]types.SqlParameter, error) {
+	var params = make([]types.SqlParameter, len(args))
+	for i, arg := range args {
+		encode := ConvertNamedValue
+		if encoder, ok := lookupParameterEncoder(registry, arg.Value); ok {
+			encode = encoder
+		}
+		sqlParam, err := encode(arg)
+		if err != nil {
+			return nil, err
+		}
+		params[i] = sqlParam
+	}
+	return params, nil
+}
@@ -0,0 +1,114 @@
+package rds_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rdsdata/types"
+	"github.com/golang/mock/gomock"
+	"github.com/krotscheck/go-rds-driver"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type testMood string
+
+func constantConverter(value interface{}) rds.FieldConverter {
+	return func(field types.Field) (interface{}, error) {
+		return value, nil
+	}
+}
+
+func Test_ConverterRegistry(t *testing.T) {
+	Convey("ConverterRegistry", t, func() {
+		registry := rds.NewConverterRegistry()
+		conf := rds.NewConfig("resourceARN", "secretARN", "database", "region")
+		conf.Converters = registry
+		field := &types.FieldMemberStringValue{Value: "happy"}
+
+		Convey("By type name", func() {
+			registry.RegisterFieldConverter("mood", constantConverter("registered"))
+
+			value, err := rds.NewPostgres(conf).GetFieldConverter(types.ColumnMetadata{TypeName: aws.String("MOOD")})(field)
+			So(err, ShouldBeNil)
+			So(value, ShouldEqual, "registered")
+		})
+
+		Convey("By predicate", func() {
+			registry.RegisterFieldConverterFunc(func(column types.ColumnMetadata) bool {
+				return strings.HasPrefix(aws.ToString(column.TypeName), "enum_")
+			}, constantConverter("predicate"))
+
+			value, err := rds.NewPostgres(conf).GetFieldConverter(types.ColumnMetadata{TypeName: aws.String("enum_mood")})(field)
+			So(err, ShouldBeNil)
+			So(value, ShouldEqual, "predicate")
+		})
+
+		Convey("Priority over built-in converters", func() {
+			registry.RegisterFieldConverter("DECIMAL", constantConverter("decimal"))
+
+			value, err := rds.NewMySQL(conf).GetFieldConverter(types.ColumnMetadata{TypeName: aws.String("DECIMAL")})(field)
+			So(err, ShouldBeNil)
+			So(value, ShouldEqual, "decimal")
+		})
+
+		Convey("Later registrations take priority", func() {
+			registry.RegisterFieldConverter("mood", constantConverter("first"))
+			registry.RegisterFieldConverter("mood", constantConverter("second"))
+
+			value, err := rds.NewPostgres(conf).GetFieldConverter(types.ColumnMetadata{TypeName: aws.String("mood")})(field)
+			So(err, ShouldBeNil)
+			So(value, ShouldEqual, "second")
+		})
+
+		Convey("Global registry", func() {
+			rds.RegisterFieldConverter("test_global_mood", constantConverter("global"))
+
+			value, err := rds.NewPostgres(conf).GetFieldConverter(types.ColumnMetadata{TypeName: aws.String("test_global_mood")})(field)
+			So(err, ShouldBeNil)
+			So(value, ShouldEqual, "global")
+
+			registry.RegisterFieldConverter("test_global_mood", constantConverter("config"))
+			value, err = rds.NewPostgres(conf).GetFieldConverter(types.ColumnMetadata{TypeName: aws.String("test_global_mood")})(field)
+			So(err, ShouldBeNil)
+			So(value, ShouldEqual, "config")
+		})
+
+		Convey("Unregistered columns use the dialect", func() {
+			value, err := rds.NewPostgres(conf).GetFieldConverter(types.ColumnMetadata{TypeName: aws.String("text")})(field)
+			So(err, ShouldBeNil)
+			So(value, ShouldEqual, "happy")
+		})
+
+		Convey("Parameter encoders", func() {
+			registry.RegisterParameterEncoder(reflect.TypeOf(testMood("")), func(arg driver.NamedValue) (types.SqlParameter, error) {
+				return types.SqlParameter{
+					Name:     aws.String(arg.Name),
+					TypeHint: types.TypeHintJson,
+					Value:    &types.FieldMemberStringValue{Value: strings.ToUpper(string(arg.Value.(testMood)))},
+				}, nil
+			})
+
+			input, err := rds.NewPostgres(conf).MigrateQuery("SELECT :mood", []driver.NamedValue{
+				{Name: "mood", Value: testMood("happy")},
+			})
+			So(err, ShouldBeNil)
+			So(input.Parameters[0].TypeHint, ShouldEqual, types.TypeHintJson)
+			So(input.Parameters[0].Value, ShouldResemble, &types.FieldMemberStringValue{Value: "HAPPY"})
+
+			Convey("CheckNamedValue", func() {
+				ctrl := gomock.NewController(t)
+				defer ctrl.Finish()
+				c := rds.NewConnection(context.Background(), NewMockAWSClientInterface(ctrl), conf, rds.NewPostgres(conf))
+				checker, ok := c.(driver.NamedValueChecker)
+				So(ok, ShouldBeTrue)
+
+				So(checker.CheckNamedValue(&driver.NamedValue{Name: "mood", Value: testMood("happy")}), ShouldBeNil)
+				So(checker.CheckNamedValue(&driver.NamedValue{Name: "other", Value: "happy"}), ShouldEqual, driver.ErrSkip)
+			})
+		})
+	})
+}
@@ -18,25 +18,82 @@ type FieldConverter func(field types.Field) (interface{}, error)
 type Dialect interface {
 	// MigrateQuery from the dialect to RDS
 	MigrateQuery(string, []driver.NamedValue) (*rdsdata.ExecuteStatementInput, error)
-	// GetFieldConverter for a given ColumnMetadata.TypeName field.
-	GetFieldConverter(columnType string) FieldConverter
+	// GetFieldConverter for a given column's metadata.
+	GetFieldConverter(column types.ColumnMetadata) FieldConverter
 	// IsIsolationLevelSupported for this dialect?
 	IsIsolationLevelSupported(level driver.IsolationLevel) bool
 	// GetTransactionSetupQuery returns the query to set up the transaction.
 	GetTransactionSetupQuery(opts driver.TxOptions) string
 }
 
+// Savepointer may be implemented by dialects whose savepoints differ from those of standard SQL.
+type Savepointer interface {
+	// GetSavepointQuery returns the query to create a savepoint within the current transaction.
+	GetSavepointQuery(name string) string
+	// GetReleaseSavepointQuery returns the query to release a savepoint.
+	GetReleaseSavepointQuery(name string) string
+	// GetRollbackToSavepointQuery returns the query to roll the transaction back to a savepoint.
+	GetRollbackToSavepointQuery(name string) string
+}
+
+// savepointerOf the dialect, defaulting to the savepoints of standard SQL.
+func savepointerOf(dialect Dialect) Savepointer {
+	if s, ok := dialect.(Savepointer); ok {
+		return s
+	}
+	return standardSavepoints{}
+}
+
+// standardSavepoints of standard SQL. Savepoint names are validated, so needn't be quoted.
+type standardSavepoints struct{}
+
+// GetSavepointQuery returns the query to create a savepoint within the current transaction.
+func (standardSavepoints) GetSavepointQuery(name string) string {
+	return "SAVEPOINT " + name
+}
+
+// GetReleaseSavepointQuery returns the query to release a savepoint.
+func (standardSavepoints) GetReleaseSavepointQuery(name string) string {
+	return "RELEASE SAVEPOINT " + name
+}
+
+// GetRollbackToSavepointQuery returns the query to roll the transaction back to a savepoint.
+func (standardSavepoints) GetRollbackToSavepointQuery(name string) string {
+	return "ROLLBACK TO SAVEPOINT " + name
+}
+
+// LastInsertIDReporter may be implemented by dialects for which the Data API doesn't return generated keys.
+type LastInsertIDReporter interface {
+	// SupportsLastInsertID reports whether the Data API returns generated keys for this dialect.
+	SupportsLastInsertID() bool
+}
+
+// supportsLastInsertID of the dialect, which is assumed unless it's a LastInsertIDReporter.
+func supportsLastInsertID(dialect Dialect) bool {
+	if r, ok := dialect.(LastInsertIDReporter); ok {
+		return r.SupportsLastInsertID()
+	}
+	return true
+}
+
+// ErrorTranslator may be implemented by dialects which recognize the errors of their database.
+type ErrorTranslator interface {
+	// TranslateError returned by the Data API into a DatabaseError, if the dialect recognizes it.
+	TranslateError(err error) error
+}
+
+// translateError returned by the Data API with the dialect, if it's an ErrorTranslator.
+func translateError(dialect Dialect, err error) error {
+	if translator, ok := dialect.(ErrorTranslator); ok {
+		return translator.TranslateError(err)
+	}
+	return err
+}
+
 // ConvertNamedValues converts passed driver.NamedValue instances into RDS SQLParameters
+// using any ParameterEncoders registered in the DefaultConverters.
 func ConvertNamedValues(args []driver.NamedValue) ([]types.SqlParameter, error) {
-	var params = make([]types.SqlParameter, len(args))
-	for i, arg := range args {
-		sqlParam, err := ConvertNamedValue(arg)
-		if err != nil {
-			return nil, err
-		}
-		params[i] = sqlParam
-	}
-	return params, nil
+	return convertNamedValuesWith(nil, args)
 }
 
 // ConvertNamedValue from a NamedValue to an SqlParameter
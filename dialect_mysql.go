@@ -3,6 +3,7 @@ package rds
 import (
 	"database/sql"
 	"database/sql/driver"
+	"encoding/binary"
 	"fmt"
 	"regexp"
 	"strconv"
@@ -14,16 +15,50 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/rdsdata/types"
 )
 
-var ordinalRegex = regexp.MustCompile("\\?{1}")
+// mysqlErrorPatterns in the messages returned by the Data API.
+var mysqlErrorPatterns = []errorPattern{
+	{fragment: "deadlock found when trying to get lock", kind: ErrDeadlock},
+	{fragment: "lock wait timeout exceeded", kind: ErrLockTimeout},
+}
+
+var mysqlTimeRegex = regexp.MustCompile(`^(-?)(\d+):(\d{2}):(\d{2})(?:\.(\d{1,9}))?$`)
+
+// mysqlZeroDateRegex matches the zero values of MySQL's DATE, DATETIME and TIMESTAMP types, such as
+// 0000-00-00 or 0000-00-00 00:00:00.000000.
+var mysqlZeroDateRegex = regexp.MustCompile(`^0000-00-00(?: 00:00:00(?:\.0+)?)?$`)
+
+var _ Savepointer = (*DialectMySQL)(nil)          // explicit compile time type check
+var _ ErrorTranslator = (*DialectMySQL)(nil)      // explicit compile time type check
+var _ LastInsertIDReporter = (*DialectMySQL)(nil) // explicit compile time type check
 
 // NewMySQL dialect from our configuration
 func NewMySQL(config *Config) Dialect {
-	return &DialectMySQL{parseTime: config.ParseTime}
+	dialect := &DialectMySQL{
+		parseTime:      config.ParseTime,
+		timeAsDuration: config.TimeAsDuration,
+		setAsSlice:     config.SetAsSlice,
+		bitAsBytes:     config.BitAsBytes,
+		zeroDate:       config.ZeroDate,
+		converters:     config.Converters,
+	}
+	// Mirror go-sql-driver, which returns zero dates as time.Time{} or as the raw string.
+	if dialect.zeroDate == "" {
+		dialect.zeroDate = ZeroDateString
+		if config.ParseTime {
+			dialect.zeroDate = ZeroDateZeroTime
+		}
+	}
+	return dialect
 }
 
 // DialectMySQL for version 5.7
 type DialectMySQL struct {
-	parseTime bool
+	parseTime      bool
+	timeAsDuration bool
+	setAsSlice     bool
+	bitAsBytes     bool
+	zeroDate       ZeroDatePolicy
+	converters     *ConverterRegistry
 }
 
 // MigrateQuery converts a mysql queries into an RDS stateement.
@@ -54,28 +89,40 @@ func (d *DialectMySQL) MigrateQuery(query string, args []driver.NamedValue) (*rd
 		}
 
 		idx := 0
-		query = ordinalRegex.ReplaceAllStringFunc(query, func(s string) string {
-			idx++ // ordinal regex are one-indexed
-			return fmt.Sprintf(":%d", idx)
+		query = mysqlSyntax.replacePlaceholders(query, func(query string, i int) (string, int) {
+			if query[i] != '?' {
+				return "", 0
+			}
+			idx++ // ordinal placeholders are one-indexed
+			return fmt.Sprintf(":%d", idx), 1
 		})
 
-		params, err := ConvertNamedValues(namedArgs)
+		params, err := convertNamedValuesWith(d.converters, namedArgs)
 		return &rdsdata.ExecuteStatementInput{
 			Parameters: params,
 			Sql:        aws.String(query),
 		}, err
 	}
 
-	params, err := ConvertNamedValues(args)
+	params, err := convertNamedValuesWith(d.converters, args)
 	return &rdsdata.ExecuteStatementInput{
 		Parameters: params,
 		Sql:        aws.String(query),
 	}, err
 }
 
+// syntax of the literals and comments of MySQL.
+func (d *DialectMySQL) syntax() *sqlSyntax {
+	return mysqlSyntax
+}
+
 // GetFieldConverter knows how to parse column results.
-func (d *DialectMySQL) GetFieldConverter(columnType string) FieldConverter {
-	switch columnType {
+func (d *DialectMySQL) GetFieldConverter(column types.ColumnMetadata) FieldConverter {
+	if converter, ok := lookupFieldConverter(d.converters, column); ok {
+		return converter
+	}
+
+	switch strings.ToUpper(aws.ToString(column.TypeName)) {
 	case "TINYINT UNSIGNED":
 		fallthrough
 	case "SMALLINT UNSIGNED":
@@ -97,51 +144,98 @@ func (d *DialectMySQL) GetFieldConverter(columnType string) FieldConverter {
 			return strconv.ParseFloat(field.(*types.FieldMemberStringValue).Value, 64)
 		}
 	case "BIT":
-		// Bit values appear to be returned as boolean values
-		return func(field types.Field) (interface{}, error) {
-			if field.(*types.FieldMemberBooleanValue).Value {
-				return 1, nil
+		// go-sql-driver returns BIT(M) as big-endian bytes, (M+7)/8 bytes wide.
+		width := int((column.Precision + 7) / 8)
+		if width < 1 || width > 8 {
+			width = 1
+		}
+		if column.Precision <= 1 && !d.bitAsBytes {
+			// BIT(1) and BOOLEAN values are returned as 0 or 1, unless bytes are asked for.
+			return func(field types.Field) (interface{}, error) {
+				switch fv := field.(type) {
+				case *types.FieldMemberBooleanValue:
+					if fv.Value {
+						return 1, nil
+					}
+					return 0, nil
+				case *types.FieldMemberLongValue:
+					return int(fv.Value), nil
+				}
+				return nil, fmt.Errorf("unrecognized BIT field: %#v", field)
 			}
-			return 0, nil
 		}
-	case "DATE":
 		return func(field types.Field) (interface{}, error) {
-			dateStringVal := field.(*types.FieldMemberStringValue).Value
-			if d.parseTime {
-				return time.Parse("2006-01-02", dateStringVal)
+			var bits uint64
+			switch fv := field.(type) {
+			case *types.FieldMemberBooleanValue:
+				// BIT(1) values are returned as boolean values
+				if fv.Value {
+					bits = 1
+				}
+			case *types.FieldMemberLongValue:
+				bits = uint64(fv.Value)
+			case *types.FieldMemberBlobValue:
+				return fv.Value, nil
+			default:
+				return nil, fmt.Errorf("unrecognized BIT field: %#v", field)
 			}
-			return dateStringVal, nil
+			buf := make([]byte, 8)
+			binary.BigEndian.PutUint64(buf, bits)
+			return buf[8-width:], nil
 		}
-	case "TIME":
+	case "ENUM":
 		return func(field types.Field) (interface{}, error) {
-			timeStringVal := field.(*types.FieldMemberStringValue).Value
-			if d.parseTime {
-				return time.Parse("15:04:05", timeStringVal)
+			return field.(*types.FieldMemberStringValue).Value, nil
+		}
+	case "SET":
+		return func(field types.Field) (interface{}, error) {
+			setStringVal := field.(*types.FieldMemberStringValue).Value
+			if !d.setAsSlice {
+				return setStringVal, nil
 			}
-			return timeStringVal, nil
+			if setStringVal == "" {
+				return []string{}, nil
+			}
+			return strings.Split(setStringVal, ","), nil
 		}
-	case "DATETIME":
+	case "GEOMETRY", "POINT", "LINESTRING", "POLYGON",
+		"MULTIPOINT", "MULTILINESTRING", "MULTIPOLYGON", "GEOMETRYCOLLECTION":
+		// Spatial values are passed through as their binary (WKB) representation.
 		return func(field types.Field) (interface{}, error) {
-			dateTimeStringVal := field.(*types.FieldMemberStringValue).Value
-			if d.parseTime {
-				return time.Parse("2006-01-02 15:04:05", dateTimeStringVal)
+			switch fv := field.(type) {
+			case *types.FieldMemberBlobValue:
+				return fv.Value, nil
+			case *types.FieldMemberStringValue:
+				return []byte(fv.Value), nil
 			}
-			return dateTimeStringVal, nil
+			return nil, fmt.Errorf("unrecognized GEOMETRY field: %#v", field)
 		}
-	case "TIMESTAMP":
+	case "DATE":
 		return func(field types.Field) (interface{}, error) {
-			timestampStringVal := field.(*types.FieldMemberStringValue).Value
-			if d.parseTime {
-				return time.Parse("2006-01-02 15:04:05", timestampStringVal)
+			return d.convertDate("2006-01-02", field.(*types.FieldMemberStringValue).Value)
+		}
+	case "TIME":
+		return func(field types.Field) (interface{}, error) {
+			timeStringVal := field.(*types.FieldMemberStringValue).Value
+			if d.timeAsDuration {
+				return parseMySQLDuration(timeStringVal)
 			}
-			return timestampStringVal, nil
+			return d.convertDate("15:04:05.999999", timeStringVal)
+		}
+	case "DATETIME", "TIMESTAMP":
+		return func(field types.Field) (interface{}, error) {
+			return d.convertDate("2006-01-02 15:04:05.999999", field.(*types.FieldMemberStringValue).Value)
 		}
 	case "YEAR":
 		// RDS sends a full date string. MySQL only returns the year.
 		return func(field types.Field) (interface{}, error) {
-			t, err := time.Parse("2006-01-02", field.(*types.FieldMemberStringValue).Value)
+			yearStringVal := field.(*types.FieldMemberStringValue).Value
+			if strings.HasPrefix(yearStringVal, "0000") {
+				return d.convertZeroDate(yearStringVal)
+			}
+			t, err := time.Parse("2006-01-02", yearStringVal)
 			if err != nil {
-				return nil, err
+				return nil, fmt.Errorf("%w %q: %w", ErrInvalidDate, yearStringVal, err)
 			}
 			if d.parseTime {
 				return t, nil
@@ -153,6 +247,59 @@ func (d *DialectMySQL) GetFieldConverter(columnType string) FieldConverter {
 	return ConvertDefaults()
 }
 
+// convertDate returns a date or time value either as the raw string or, with parse_time, as a
+// time.Time. Zero dates are handled according to the configured ZeroDatePolicy, while values that
+// can't be parsed return an error wrapping ErrInvalidDate.
+func (d *DialectMySQL) convertDate(layout string, value string) (interface{}, error) {
+	if mysqlZeroDateRegex.MatchString(value) {
+		return d.convertZeroDate(value)
+	}
+	if !d.parseTime {
+		return value, nil
+	}
+	t, err := time.Parse(layout, value)
+	if err != nil {
+		return nil, fmt.Errorf("%w %q: %w", ErrInvalidDate, value, err)
+	}
+	return t, nil
+}
+
+// convertZeroDate applies the ZeroDatePolicy to a zero date.
+func (d *DialectMySQL) convertZeroDate(value string) (interface{}, error) {
+	switch d.zeroDate {
+	case ZeroDateNull:
+		return nil, nil
+	case ZeroDateZeroTime:
+		return time.Time{}, nil
+	case ZeroDateString:
+		return value, nil
+	}
+	return nil, fmt.Errorf("%w %q", ErrInvalidDate, value)
+}
+
+// parseMySQLDuration converts a TIME value, such as "-838:59:59.000000", into a time.Duration.
+func parseMySQLDuration(value string) (time.Duration, error) {
+	match := mysqlTimeRegex.FindStringSubmatch(value)
+	if match == nil {
+		return 0, fmt.Errorf("invalid TIME value %q", value)
+	}
+	hours, _ := strconv.ParseInt(match[2], 10, 64)
+	minutes, _ := strconv.ParseInt(match[3], 10, 64)
+	seconds, _ := strconv.ParseInt(match[4], 10, 64)
+	duration := time.Duration(hours)*time.Hour +
+		time.Duration(minutes)*time.Minute +
+		time.Duration(seconds)*time.Second
+	if match[5] != "" {
+		// Right-pad the fraction to nanosecond precision.
+		nanos, _ := strconv.ParseInt((match[5] + "000000000")[:9], 10, 64)
+		duration += time.Duration(nanos)
+	}
+	if match[1] == "-" {
+		duration = -duration
+	}
+	return duration, nil
+}
+
 // IsIsolationLevelSupported for mysql?
 func (d *DialectMySQL) IsIsolationLevelSupported(level driver.IsolationLevel) bool {
 	// SupportedIsolationLevels for the dialect
@@ -183,3 +330,28 @@ func (d *DialectMySQL) GetTransactionSetupQuery(opts driver.TxOptions) string {
 	}
 	return fmt.Sprintf("SET TRANSACTION %s", strings.Join(clause, ", "))
 }
+
+// GetSavepointQuery returns the query to create a savepoint within the current transaction.
+func (d *DialectMySQL) GetSavepointQuery(name string) string {
+	return fmt.Sprintf("SAVEPOINT `%s`", name)
+}
+
+// GetReleaseSavepointQuery returns the query to release a savepoint.
+func (d *DialectMySQL) GetReleaseSavepointQuery(name string) string {
+	return fmt.Sprintf("RELEASE SAVEPOINT `%s`", name)
+}
+
+// GetRollbackToSavepointQuery returns the query to roll the transaction back to a savepoint.
+func (d *DialectMySQL) GetRollbackToSavepointQuery(name string) string {
+	return fmt.Sprintf("ROLLBACK TO SAVEPOINT `%s`", name)
+}
+
+// TranslateError from the Data API into a DatabaseError.
+func (d *DialectMySQL) TranslateError(err error) error {
+	return classifyError(err, mysqlErrorPatterns)
+}
+
+// SupportsLastInsertID is true, as the Data API returns AUTO_INCREMENT values as generated fields.
+func (d *DialectMySQL) SupportsLastInsertID() bool {
+	return true
+}
@@ -0,0 +1,44 @@
+package rds
+
+import (
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rdsdata/types"
+)
+
+// mysql8ErrorPatterns extend those of MySQL 5.7 with errors introduced in 8.0.
+var mysql8ErrorPatterns = append([]errorPattern{
+	{fragment: "check constraint", kind: ErrCheckViolation},
+	{fragment: "nowait is set", kind: ErrLockTimeout},
+}, mysqlErrorPatterns...)
+
+// mysql8TypeAliases maps type names reported by MySQL 8.0 onto their MySQL 5.7 equivalents.
+var mysql8TypeAliases = map[string]string{
+	"GEOMCOLLECTION": "GEOMETRYCOLLECTION",
+}
+
+// NewMySQL8 dialect from our configuration
+func NewMySQL8(config *Config) Dialect {
+	return &DialectMySQL8{DialectMySQL: NewMySQL(config).(*DialectMySQL)}
+}
+
+// DialectMySQL8 for Aurora MySQL 3, which is compatible with MySQL 8.0. Only its type aliases and
+// errors differ from MySQL 5.7: transactions are set up exactly alike, as SET TRANSACTION is
+// unaffected by the renamed tx_isolation variables, and every other type is converted alike.
+type DialectMySQL8 struct {
+	*DialectMySQL
+}
+
+// GetFieldConverter knows how to parse column results, once the type names of MySQL 8.0 are aliased.
+func (d *DialectMySQL8) GetFieldConverter(column types.ColumnMetadata) FieldConverter {
+	if alias, ok := mysql8TypeAliases[strings.ToUpper(aws.ToString(column.TypeName))]; ok {
+		column.TypeName = aws.String(alias)
+	}
+	return d.DialectMySQL.GetFieldConverter(column)
+}
+
+// TranslateError from the Data API into a DatabaseError.
+func (d *DialectMySQL8) TranslateError(err error) error {
+	return classifyError(err, mysql8ErrorPatterns)
+}
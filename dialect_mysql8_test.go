@@ -0,0 +1,115 @@
+package rds_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/rdsdata"
+	"github.com/aws/aws-sdk-go-v2/service/rdsdata/types"
+	"github.com/golang/mock/gomock"
+	"github.com/krotscheck/go-rds-driver"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func Test_DialectMySQL8(t *testing.T) {
+	conf := rds.NewConfig("resourceARN", "secretARN", "database", "region")
+
+	Convey("DialectMySQL8", t, func() {
+		dialect := rds.NewMySQL8(conf)
+
+		Convey("Detected from the version", func() {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockRDS := NewMockAWSClientInterface(ctrl)
+			mockRDS.EXPECT().
+				ExecuteStatement(context.TODO(), gomock.Any()).
+				Return(&rdsdata.ExecuteStatementOutput{
+					Records: [][]types.Field{
+						{&types.FieldMemberStringValue{Value: "8.0.28"}},
+					},
+				}, nil)
+
+			detected, err := rds.NewConnector(rds.NewDriver(), mockRDS, conf).Wakeup()
+			So(err, ShouldBeNil)
+			So(detected, ShouldHaveSameTypeAs, &rds.DialectMySQL8{})
+		})
+
+		Convey("GetFieldConverter", func() {
+			wkb := []byte{0x00, 0x00, 0x00, 0x00, 0x01, 0x07}
+			value, err := dialect.GetFieldConverter(mysqlColumn("GEOMCOLLECTION", 0))(&types.FieldMemberBlobValue{Value: wkb})
+			So(err, ShouldBeNil)
+			So(value, ShouldResemble, wkb)
+
+			value, err = dialect.GetFieldConverter(mysqlColumn("BIGINT UNSIGNED", 20))(&types.FieldMemberLongValue{Value: 8})
+			So(err, ShouldBeNil)
+			So(value, ShouldEqual, uint64(8))
+
+			registered := *conf
+			registered.Converters = rds.NewConverterRegistry()
+			registered.Converters.RegisterFieldConverter("GEOMETRYCOLLECTION", func(field types.Field) (interface{}, error) {
+				return "registered", nil
+			})
+			value, err = rds.NewMySQL8(&registered).GetFieldConverter(mysqlColumn("GEOMCOLLECTION", 0))(&types.FieldMemberBlobValue{Value: wkb})
+			So(err, ShouldBeNil)
+			So(value, ShouldEqual, "registered")
+		})
+
+		Convey("GetTransactionSetupQuery", func() {
+			So(dialect.GetTransactionSetupQuery(readOnlyTxOptions), ShouldEqual, "SET TRANSACTION READ ONLY")
+		})
+
+		Convey("Matches MySQL 5.7 beyond its aliases and errors", func() {
+			mysql := rds.NewMySQL(conf)
+			for _, level := range []sql.IsolationLevel{sql.LevelDefault, sql.LevelReadUncommitted, sql.LevelReadCommitted,
+				sql.LevelRepeatableRead, sql.LevelSerializable} {
+				for _, readOnly := range []bool{false, true} {
+					opts := driver.TxOptions{Isolation: driver.IsolationLevel(level), ReadOnly: readOnly}
+					So(dialect.IsIsolationLevelSupported(opts.Isolation), ShouldEqual, mysql.IsIsolationLevelSupported(opts.Isolation))
+					So(dialect.GetTransactionSetupQuery(opts), ShouldEqual, mysql.GetTransactionSetupQuery(opts))
+				}
+			}
+
+			fields := map[string]types.Field{
+				"JSON":      &types.FieldMemberStringValue{Value: `{"a": 1}`},
+				"DATETIME":  &types.FieldMemberStringValue{Value: "2021-01-02 03:04:05"},
+				"TIME":      &types.FieldMemberStringValue{Value: "03:04:05"},
+				"YEAR":      &types.FieldMemberStringValue{Value: "2021-01-01"},
+				"SET":       &types.FieldMemberStringValue{Value: "ONE,TWO"},
+				"GEOMETRY":  &types.FieldMemberBlobValue{Value: []byte{0x01}},
+				"DECIMAL":   &types.FieldMemberStringValue{Value: "1.50"},
+				"TINYINT":   &types.FieldMemberLongValue{Value: 1},
+				"VARBINARY": &types.FieldMemberBlobValue{Value: []byte{0x02}},
+			}
+			for typeName, field := range fields {
+				expected, expectedErr := mysql.GetFieldConverter(mysqlColumn(typeName, 0))(field)
+				value, err := dialect.GetFieldConverter(mysqlColumn(typeName, 0))(field)
+				So(err, ShouldResemble, expectedErr)
+				So(value, ShouldResemble, expected)
+			}
+		})
+
+		Convey("TranslateError", func() {
+			dialect := dialect.(rds.ErrorTranslator)
+			err := dialect.TranslateError(fmt.Errorf("BadRequestException: Check constraint 'positive_balance' is violated."))
+			So(errors.Is(err, rds.ErrCheckViolation), ShouldBeTrue)
+
+			err = dialect.TranslateError(fmt.Errorf("BadRequestException: Statement aborted because lock(s) could not be acquired immediately and NOWAIT is set."))
+			So(errors.Is(err, rds.ErrLockTimeout), ShouldBeTrue)
+
+			err = dialect.TranslateError(fmt.Errorf("BadRequestException: Deadlock found when trying to get lock; try restarting transaction"))
+			So(errors.Is(err, rds.ErrDeadlock), ShouldBeTrue)
+
+			var dbErr *rds.DatabaseError
+			So(errors.As(err, &dbErr), ShouldBeTrue)
+			So(dbErr.Kind, ShouldEqual, rds.ErrDeadlock)
+
+			original := fmt.Errorf("BadRequestException: Table 'missing' doesn't exist")
+			So(dialect.TranslateError(original), ShouldEqual, original)
+			So(dialect.TranslateError(nil), ShouldBeNil)
+		})
+	})
+}
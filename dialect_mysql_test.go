@@ -0,0 +1,362 @@
+package rds_test
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rdsdata"
+	"github.com/aws/aws-sdk-go-v2/service/rdsdata/types"
+	"github.com/golang/mock/gomock"
+	"github.com/krotscheck/go-rds-driver"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+var readOnlyTxOptions = driver.TxOptions{
+	Isolation: driver.IsolationLevel(sql.LevelDefault),
+	ReadOnly:  true,
+}
+
+func mysqlColumn(typeName string, precision int32) types.ColumnMetadata {
+	return types.ColumnMetadata{TypeName: aws.String(typeName), Precision: precision}
+}
+
+func Test_DialectMySQL(t *testing.T) {
+	Convey("GetFieldConverter", t, func() {
+		conf := rds.NewConfig("resourceARN", "secretARN", "database", "region")
+
+		Convey("BIT(M)", func() {
+			dialect := rds.NewMySQL(conf)
+
+			Convey("BIT(1) as boolean", func() {
+				value, err := dialect.GetFieldConverter(mysqlColumn("BIT", 1))(&types.FieldMemberBooleanValue{Value: true})
+				So(err, ShouldBeNil)
+				So(value, ShouldEqual, 1)
+			})
+
+			Convey("BIT(1) as bytes", func() {
+				bytesConf := rds.NewConfig("resourceARN", "secretARN", "database", "region")
+				bytesConf.BitAsBytes = true
+				value, err := rds.NewMySQL(bytesConf).GetFieldConverter(mysqlColumn("BIT", 1))(&types.FieldMemberBooleanValue{Value: true})
+				So(err, ShouldBeNil)
+				So(value, ShouldResemble, []byte{0x01})
+			})
+
+			Convey("BIT(12) as long", func() {
+				value, err := dialect.GetFieldConverter(mysqlColumn("BIT", 12))(&types.FieldMemberLongValue{Value: 0x0A0B})
+				So(err, ShouldBeNil)
+				So(value, ShouldResemble, []byte{0x0A, 0x0B})
+			})
+
+			Convey("BIT(16) as blob", func() {
+				value, err := dialect.GetFieldConverter(mysqlColumn("BIT", 16))(&types.FieldMemberBlobValue{Value: []byte{0x01, 0x02}})
+				So(err, ShouldBeNil)
+				So(value, ShouldResemble, []byte{0x01, 0x02})
+			})
+		})
+
+		Convey("ENUM", func() {
+			dialect := rds.NewMySQL(conf)
+			value, err := dialect.GetFieldConverter(mysqlColumn("ENUM", 0))(&types.FieldMemberStringValue{Value: "ONE"})
+			So(err, ShouldBeNil)
+			So(value, ShouldEqual, "ONE")
+		})
+
+		Convey("SET", func() {
+			Convey("As string", func() {
+				dialect := rds.NewMySQL(conf)
+				value, err := dialect.GetFieldConverter(mysqlColumn("SET", 0))(&types.FieldMemberStringValue{Value: "ONE,TWO"})
+				So(err, ShouldBeNil)
+				So(value, ShouldEqual, "ONE,TWO")
+			})
+
+			Convey("As slice", func() {
+				conf.SetAsSlice = true
+				dialect := rds.NewMySQL(conf)
+				value, err := dialect.GetFieldConverter(mysqlColumn("SET", 0))(&types.FieldMemberStringValue{Value: "ONE,TWO"})
+				So(err, ShouldBeNil)
+				So(value, ShouldResemble, []string{"ONE", "TWO"})
+
+				value, err = dialect.GetFieldConverter(mysqlColumn("SET", 0))(&types.FieldMemberStringValue{Value: ""})
+				So(err, ShouldBeNil)
+				So(value, ShouldResemble, []string{})
+			})
+		})
+
+		Convey("GEOMETRY", func() {
+			dialect := rds.NewMySQL(conf)
+			wkb := []byte{0x00, 0x00, 0x00, 0x00, 0x01, 0x01, 0x00, 0x00, 0x00}
+			value, err := dialect.GetFieldConverter(mysqlColumn("POINT", 0))(&types.FieldMemberBlobValue{Value: wkb})
+			So(err, ShouldBeNil)
+			So(value, ShouldResemble, wkb)
+		})
+
+		Convey("Fractional seconds", func() {
+			conf.ParseTime = true
+			dialect := rds.NewMySQL(conf)
+			expected := time.Date(2021, 8, 1, 12, 30, 45, 123456000, time.UTC)
+
+			for _, typeName := range []string{"DATETIME", "TIMESTAMP"} {
+				value, err := dialect.GetFieldConverter(mysqlColumn(typeName, 26))(&types.FieldMemberStringValue{Value: "2021-08-01 12:30:45.123456"})
+				So(err, ShouldBeNil)
+				So(value, ShouldEqual, expected)
+			}
+		})
+
+		Convey("TIME", func() {
+			Convey("As duration", func() {
+				conf.TimeAsDuration = true
+				dialect := rds.NewMySQL(conf)
+				converter := dialect.GetFieldConverter(mysqlColumn("TIME", 10))
+
+				value, err := converter(&types.FieldMemberStringValue{Value: "838:59:59"})
+				So(err, ShouldBeNil)
+				So(value, ShouldEqual, 838*time.Hour+59*time.Minute+59*time.Second)
+
+				value, err = converter(&types.FieldMemberStringValue{Value: "-01:02:03.5"})
+				So(err, ShouldBeNil)
+				So(value, ShouldEqual, -(time.Hour + 2*time.Minute + 3*time.Second + 500*time.Millisecond))
+
+				_, err = converter(&types.FieldMemberStringValue{Value: "invalid"})
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("As string", func() {
+				dialect := rds.NewMySQL(conf)
+				value, err := dialect.GetFieldConverter(mysqlColumn("TIME", 10))(&types.FieldMemberStringValue{Value: "838:59:59"})
+				So(err, ShouldBeNil)
+				So(value, ShouldEqual, "838:59:59")
+			})
+		})
+
+		Convey("Zero dates", func() {
+			zero := []struct {
+				name  string
+				value string
+			}{
+				{"zero date", "0000-00-00 00:00:00"},
+				{"zero date with fractional seconds", "0000-00-00 00:00:00.000000"},
+			}
+
+			for _, typeName := range []string{"DATETIME", "TIMESTAMP"} {
+				Convey(typeName, func() {
+					for _, tc := range zero {
+						field := &types.FieldMemberStringValue{Value: tc.value}
+
+						Convey(tc.name, func() {
+							Convey("error", func() {
+								conf.ZeroDate = rds.ZeroDateError
+								_, err := rds.NewMySQL(conf).GetFieldConverter(mysqlColumn(typeName, 19))(field)
+								So(errors.Is(err, rds.ErrInvalidDate), ShouldBeTrue)
+							})
+
+							Convey("null", func() {
+								conf.ZeroDate = rds.ZeroDateNull
+								value, err := rds.NewMySQL(conf).GetFieldConverter(mysqlColumn(typeName, 19))(field)
+								So(err, ShouldBeNil)
+								So(value, ShouldBeNil)
+							})
+
+							Convey("zero_time", func() {
+								conf.ZeroDate = rds.ZeroDateZeroTime
+								value, err := rds.NewMySQL(conf).GetFieldConverter(mysqlColumn(typeName, 19))(field)
+								So(err, ShouldBeNil)
+								So(value, ShouldEqual, time.Time{})
+							})
+
+							Convey("string", func() {
+								conf.ZeroDate = rds.ZeroDateString
+								value, err := rds.NewMySQL(conf).GetFieldConverter(mysqlColumn(typeName, 19))(field)
+								So(err, ShouldBeNil)
+								So(value, ShouldEqual, field.Value)
+							})
+						})
+					}
+				})
+			}
+
+			Convey("DATE", func() {
+				conf.ZeroDate = rds.ZeroDateNull
+				value, err := rds.NewMySQL(conf).GetFieldConverter(mysqlColumn("DATE", 10))(&types.FieldMemberStringValue{Value: "0000-00-00"})
+				So(err, ShouldBeNil)
+				So(value, ShouldBeNil)
+			})
+
+			Convey("YEAR", func() {
+				conf.ZeroDate = rds.ZeroDateNull
+				for _, year := range []string{"0000-00-00", "0000-01-01"} {
+					value, err := rds.NewMySQL(conf).GetFieldConverter(mysqlColumn("YEAR", 4))(&types.FieldMemberStringValue{Value: year})
+					So(err, ShouldBeNil)
+					So(value, ShouldBeNil)
+				}
+			})
+
+			Convey("Invalid dates aren't zero dates", func() {
+				invalid := []struct {
+					name  string
+					value string
+				}{
+					{"zero month", "2021-00-15 00:00:00"},
+					{"zero day", "2021-08-00 00:00:00"},
+					{"nonexistent day", "2021-02-30 00:00:00"},
+					{"out of range year", "10000-01-01 00:00:00"},
+				}
+
+				for _, tc := range invalid {
+					field := &types.FieldMemberStringValue{Value: tc.value}
+
+					Convey(tc.name, func() {
+						for _, policy := range []rds.ZeroDatePolicy{"", rds.ZeroDateError, rds.ZeroDateNull, rds.ZeroDateZeroTime, rds.ZeroDateString} {
+							conf.ZeroDate = policy
+							conf.ParseTime = true
+							_, err := rds.NewMySQL(conf).GetFieldConverter(mysqlColumn("DATETIME", 19))(field)
+							So(errors.Is(err, rds.ErrInvalidDate), ShouldBeTrue)
+
+							conf.ParseTime = false
+							value, err := rds.NewMySQL(conf).GetFieldConverter(mysqlColumn("DATETIME", 19))(field)
+							So(err, ShouldBeNil)
+							So(value, ShouldEqual, field.Value)
+						}
+					})
+				}
+			})
+
+			Convey("TIME", func() {
+				conf.ParseTime = true
+				conf.ZeroDate = rds.ZeroDateNull
+				converter := rds.NewMySQL(conf).GetFieldConverter(mysqlColumn("TIME", 10))
+
+				value, err := converter(&types.FieldMemberStringValue{Value: "00:00:00"})
+				So(err, ShouldBeNil)
+				So(value, ShouldEqual, time.Date(0, 1, 1, 0, 0, 0, 0, time.UTC))
+
+				_, err = converter(&types.FieldMemberStringValue{Value: "838:59:59"})
+				So(errors.Is(err, rds.ErrInvalidDate), ShouldBeTrue)
+			})
+
+			Convey("Default mirrors go-sql-driver", func() {
+				field := &types.FieldMemberStringValue{Value: "0000-00-00 00:00:00"}
+
+				value, err := rds.NewMySQL(conf).GetFieldConverter(mysqlColumn("DATETIME", 19))(field)
+				So(err, ShouldBeNil)
+				So(value, ShouldEqual, field.Value)
+
+				conf.ParseTime = true
+				value, err = rds.NewMySQL(conf).GetFieldConverter(mysqlColumn("DATETIME", 19))(field)
+				So(err, ShouldBeNil)
+				So(value, ShouldEqual, time.Time{})
+			})
+		})
+	})
+
+	Convey("TranslateError", t, func() {
+		dialect := rds.NewMySQL(rds.NewConfig("resourceARN", "secretARN", "database", "region")).(rds.ErrorTranslator)
+
+		err := dialect.TranslateError(fmt.Errorf("BadRequestException: Lock wait timeout exceeded; try restarting transaction"))
+		So(errors.Is(err, rds.ErrLockTimeout), ShouldBeTrue)
+
+		err = dialect.TranslateError(fmt.Errorf("BadRequestException: Check constraint 'positive_balance' is violated."))
+		So(errors.Is(err, rds.ErrCheckViolation), ShouldBeFalse)
+	})
+
+	Convey("Scanning BIT(1)", t, func() {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		mockRDS := NewMockAWSClientInterface(ctrl)
+		conf := rds.NewConfig("resourceARN", "secretARN", "database", "region")
+		output := &rdsdata.ExecuteStatementOutput{
+			ColumnMetadata: []types.ColumnMetadata{
+				{Label: aws.String("flag"), TypeName: aws.String("BIT"), Precision: 1},
+			},
+			Records: [][]types.Field{{&types.FieldMemberBooleanValue{Value: true}}},
+		}
+		query := func(conf *rds.Config, dest interface{}) error {
+			ExpectWakeup(mockRDS, conf)
+			ExpectStatement(mockRDS, "SELECT flag FROM flags").Return(output, nil)
+			db := sql.OpenDB(rds.NewConnector(rds.NewDriver(), mockRDS, conf))
+			defer db.Close()
+			return db.QueryRow("SELECT flag FROM flags").Scan(dest)
+		}
+
+		Convey("Into a bool", func() {
+			var flag bool
+			So(query(conf, &flag), ShouldBeNil)
+			So(flag, ShouldBeTrue)
+		})
+
+		Convey("Into an int", func() {
+			var flag int
+			So(query(conf, &flag), ShouldBeNil)
+			So(flag, ShouldEqual, 1)
+		})
+
+		Convey("Into bytes", func() {
+			conf.BitAsBytes = true
+			var flag []byte
+			So(query(conf, &flag), ShouldBeNil)
+			So(flag, ShouldResemble, []byte{0x01})
+		})
+	})
+}
+
+func FuzzDialectMySQL_MigrateQuery(f *testing.F) {
+	for _, query := range queryFixtures {
+		for _, literal := range literalFixtures {
+			f.Add(literal, query)
+		}
+	}
+	dialect := rds.NewMySQL(rds.NewConfig("resourceARN", "secretARN", "database", "region"))
+	args := []driver.NamedValue{{Ordinal: 1, Value: int64(1)}}
+	migrate := func(t *testing.T, query string) string {
+		input, err := dialect.MigrateQuery(query, args)
+		if err != nil {
+			t.Fatalf("%q: %v", query, err)
+		}
+		return aws.ToString(input.Sql)
+	}
+	// placeholders rewritten by the migration, which may only replace ? with the placeholders :1 to :n in order.
+	placeholders := func(t *testing.T, query string, migrated string) int {
+		n, j := 0, 0
+		for i := 0; i < len(query); i++ {
+			if query[i] == '?' && (j == len(migrated) || migrated[j] != '?') {
+				n++
+				placeholder := ":" + strconv.Itoa(n)
+				if !strings.HasPrefix(migrated[j:], placeholder) {
+					t.Fatalf("%q migrated to %q, lacking %s", query, migrated, placeholder)
+				}
+				j += len(placeholder)
+				continue
+			}
+			if j == len(migrated) || query[i] != migrated[j] {
+				t.Fatalf("%q migrated to %q, which differs at %d", query, migrated, j)
+			}
+			j++
+		}
+		if j != len(migrated) {
+			t.Fatalf("%q migrated to %q, which is longer", query, migrated)
+		}
+		return n
+	}
+
+	f.Fuzz(func(t *testing.T, literal string, query string) {
+		n := placeholders(t, query, migrate(t, query))
+		if !strings.ContainsAny(query, "'\"`#-/\\") && n != strings.Count(query, "?") {
+			t.Fatalf("%q has %d placeholders, yet %d were rewritten", query, strings.Count(query, "?"), n)
+		}
+		for _, quoted := range mysqlLiterals(literal) {
+			migrated := migrate(t, quoted+" "+query)
+			if !strings.HasPrefix(migrated, quoted+" ") {
+				t.Fatalf("%q rewritten within its literal to %q", quoted+" "+query, migrated)
+			}
+			if placeholders(t, quoted+" "+query, migrated) != n {
+				t.Fatalf("%q counted the placeholders within its literal", quoted+" "+query)
+			}
+		}
+	})
+}
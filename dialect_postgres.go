@@ -7,22 +7,37 @@ import (
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/rdsdata"
 	"github.com/aws/aws-sdk-go-v2/service/rdsdata/types"
-	"regexp"
 	"strconv"
 	"strings"
 	"time"
 )
 
-var postgresRegex = regexp.MustCompile("\\$([0-9]+)")
+var _ Savepointer = (*DialectPostgres)(nil)          // explicit compile time type check
+var _ ErrorTranslator = (*DialectPostgres)(nil)      // explicit compile time type check
+var _ LastInsertIDReporter = (*DialectPostgres)(nil) // explicit compile time type check
+
+// postgresErrorPatterns in the messages returned by the Data API, which include the SQLSTATE.
+var postgresErrorPatterns = []errorPattern{
+	{fragment: "sqlstate: 40001", kind: ErrSerializationFailure},
+	{fragment: "could not serialize access", kind: ErrSerializationFailure},
+	{fragment: "sqlstate: 40p01", kind: ErrDeadlock},
+	{fragment: "deadlock detected", kind: ErrDeadlock},
+	{fragment: "sqlstate: 55p03", kind: ErrLockTimeout},
+	{fragment: "sqlstate: 23514", kind: ErrCheckViolation},
+}
 
 // NewPostgres dialect from our configuration
 func NewPostgres(config *Config) Dialect {
-	return &DialectPostgres{parseTime: config.ParseTime}
+	return &DialectPostgres{
+		parseTime:  config.ParseTime,
+		converters: config.Converters,
+	}
 }
 
 // DialectPostgres is for postgres 10.14 as supported by aurora serverless
 type DialectPostgres struct {
-	parseTime bool
+	parseTime  bool
+	converters *ConverterRegistry
 }
 
 // MigrateQuery from Postgres to RDS.
@@ -52,26 +67,38 @@ func (d *DialectPostgres) MigrateQuery(query string, args []driver.NamedValue) (
 			}
 		}
 
-		query = postgresRegex.ReplaceAllStringFunc(query, func(s string) string {
-			return strings.Replace(s, "$", ":", 1)
+		query = postgresSyntax.replacePlaceholders(query, func(query string, i int) (string, int) {
+			if query[i] != '$' || i+1 == len(query) || query[i+1] < '0' || query[i+1] > '9' {
+				return "", 0
+			}
+			return ":", 1
 		})
 
-		params, err := ConvertNamedValues(namedArgs)
+		params, err := convertNamedValuesWith(d.converters, namedArgs)
 		return &rdsdata.ExecuteStatementInput{
 			Parameters: params,
 			Sql:        aws.String(query),
 		}, err
 	}
-	params, err := ConvertNamedValues(args)
+	params, err := convertNamedValuesWith(d.converters, args)
 	return &rdsdata.ExecuteStatementInput{
 		Parameters: params,
 		Sql:        aws.String(query),
 	}, err
 }
 
+// syntax of the literals and comments of Postgres.
+func (d *DialectPostgres) syntax() *sqlSyntax {
+	return postgresSyntax
+}
+
 // GetFieldConverter knows how to parse response data.
-func (d *DialectPostgres) GetFieldConverter(columnType string) FieldConverter {
-	switch strings.ToLower(columnType) {
+func (d *DialectPostgres) GetFieldConverter(column types.ColumnMetadata) FieldConverter {
+	if converter, ok := lookupFieldConverter(d.converters, column); ok {
+		return converter
+	}
+
+	switch strings.ToLower(aws.ToString(column.TypeName)) {
 	case "numeric":
 		return func(field types.Field) (interface{}, error) {
 			return strconv.ParseFloat(field.(*types.FieldMemberStringValue).Value, 64)
@@ -159,3 +186,28 @@ func (d *DialectPostgres) GetTransactionSetupQuery(opts driver.TxOptions) string
 	}
 	return fmt.Sprintf("SET TRANSACTION %s", strings.Join(clause, ", "))
 }
+
+// GetSavepointQuery returns the query to create a savepoint within the current transaction.
+func (d *DialectPostgres) GetSavepointQuery(name string) string {
+	return fmt.Sprintf(`SAVEPOINT "%s"`, name)
+}
+
+// GetReleaseSavepointQuery returns the query to release a savepoint.
+func (d *DialectPostgres) GetReleaseSavepointQuery(name string) string {
+	return fmt.Sprintf(`RELEASE SAVEPOINT "%s"`, name)
+}
+
+// GetRollbackToSavepointQuery returns the query to roll the transaction back to a savepoint.
+func (d *DialectPostgres) GetRollbackToSavepointQuery(name string) string {
+	return fmt.Sprintf(`ROLLBACK TO SAVEPOINT "%s"`, name)
+}
+
+// TranslateError from the Data API into a DatabaseError.
+func (d *DialectPostgres) TranslateError(err error) error {
+	return classifyError(err, postgresErrorPatterns)
+}
+
+// SupportsLastInsertID is false, as generated keys must be requested with a RETURNING clause.
+func (d *DialectPostgres) SupportsLastInsertID() bool {
+	return false
+}
@@ -0,0 +1,93 @@
+package rds
+
+import (
+	"strings"
+	"sync"
+)
+
+// DialectDetector reports whether a database, given the result of its VERSION() query, uses a dialect.
+type DialectDetector func(version string) bool
+
+// DialectFactory creates a dialect from our configuration.
+type DialectFactory func(config *Config) Dialect
+
+// Dialect names built into the driver.
+const (
+	DialectNameMySQL    = "mysql"
+	DialectNamePostgres = "postgres"
+	DialectNameMySQL8   = "mysql8"
+)
+
+type registeredDialect struct {
+	name    string
+	detect  DialectDetector
+	factory DialectFactory
+}
+
+var dialectsMu sync.RWMutex
+var dialects []registeredDialect
+
+func init() {
+	// MySQL is registered first, so that it's detected last and acts as the fallback.
+	RegisterDialect(DialectNameMySQL, func(version string) bool {
+		return true
+	}, NewMySQL)
+	RegisterDialect(DialectNamePostgres, func(version string) bool {
+		return strings.Contains(strings.ToLower(version), "postgres")
+	}, NewPostgres)
+	RegisterDialect(DialectNameMySQL8, func(version string) bool {
+		return strings.HasPrefix(version, "8.")
+	}, NewMySQL8)
+}
+
+// RegisterDialect makes a dialect available for detection and for selection via the dialect DSN option.
+// Dialects are detected in the reverse order of registration, so variants of the built-in dialects
+// are tried first. Registering an existing name replaces it, keeping its place in the order.
+func RegisterDialect(name string, detect DialectDetector, factory DialectFactory) {
+	dialectsMu.Lock()
+	defer dialectsMu.Unlock()
+	registered := registeredDialect{name: name, detect: detect, factory: factory}
+	for i, d := range dialects {
+		if d.name == name {
+			dialects[i] = registered
+			return
+		}
+	}
+	dialects = append(dialects, registered)
+}
+
+// unregisterDialect by its registered name, if any.
+func unregisterDialect(name string) {
+	dialectsMu.Lock()
+	defer dialectsMu.Unlock()
+	for i, d := range dialects {
+		if d.name == name {
+			dialects = append(dialects[:i], dialects[i+1:]...)
+			return
+		}
+	}
+}
+
+// lookupDialect by its registered name.
+func lookupDialect(name string) (DialectFactory, bool) {
+	dialectsMu.RLock()
+	defer dialectsMu.RUnlock()
+	for _, d := range dialects {
+		if d.name == name {
+			return d.factory, true
+		}
+	}
+	return nil, false
+}
+
+// detectDialect from the result of the VERSION() query.
+func detectDialect(version string) (DialectFactory, bool) {
+	dialectsMu.RLock()
+	defer dialectsMu.RUnlock()
+	for i := len(dialects) - 1; i >= 0; i-- {
+		if dialects[i].detect(version) {
+			return dialects[i].factory, true
+		}
+	}
+	return nil, false
+}
@@ -1,11 +1,17 @@
 package rds_test
 
 import (
+	"context"
 	"database/sql/driver"
+	"errors"
+	"fmt"
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rdsdata"
 	"github.com/aws/aws-sdk-go-v2/service/rdsdata/types"
+	"github.com/golang/mock/gomock"
 	"github.com/krotscheck/go-rds-driver"
 	. "github.com/smartystreets/goconvey/convey"
+	"strings"
 	"testing"
 )
 
@@ -36,4 +42,107 @@ func Test_Dialect(t *testing.T) {
 			}
 		})
 	})
+
+	Convey("Postgres TranslateError", t, func() {
+		dialect := rds.NewPostgres(rds.NewConfig("resourceARN", "secretARN", "database", "region")).(rds.ErrorTranslator)
+
+		err := dialect.TranslateError(fmt.Errorf("BadRequestException: ERROR: could not serialize access due to concurrent update; SQLState: 40001"))
+		So(errors.Is(err, rds.ErrSerializationFailure), ShouldBeTrue)
+
+		err = dialect.TranslateError(fmt.Errorf("BadRequestException: ERROR: deadlock detected; SQLState: 40P01"))
+		So(errors.Is(err, rds.ErrDeadlock), ShouldBeTrue)
+	})
+
+	Convey("Optional interfaces", t, func() {
+		ctx := context.Background()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		mockRDS := NewMockAWSClientInterface(ctrl)
+		conf := rds.NewConfig("resourceARN", "secretARN", "database", "region")
+		conn := rds.NewConnection(ctx, mockRDS, conf, plainDialect{rds.NewMySQL(conf)}).(*rds.Connection)
+
+		Convey("Errors aren't translated", func() {
+			ExpectStatement(mockRDS, "UPDATE accounts SET balance = 0").
+				Return(nil, fmt.Errorf("BadRequestException: Deadlock found when trying to get lock; try restarting transaction"))
+			_, err := conn.ExecContext(ctx, "UPDATE accounts SET balance = 0", nil)
+			So(err, ShouldNotBeNil)
+			So(errors.Is(err, rds.ErrDeadlock), ShouldBeFalse)
+		})
+
+		Convey("Savepoints are those of standard SQL", func() {
+			ExpectBeginTransaction(mockRDS, "transactionID")
+			_, err := conn.BeginTx(ctx, driver.TxOptions{})
+			So(err, ShouldBeNil)
+			ExpectStatement(mockRDS, "SAVEPOINT outer").Return(&rdsdata.ExecuteStatementOutput{}, nil)
+			ExpectStatement(mockRDS, "ROLLBACK TO SAVEPOINT outer").Return(&rdsdata.ExecuteStatementOutput{}, nil)
+
+			sp, err := conn.Savepoint(ctx, "outer")
+			So(err, ShouldBeNil)
+			So(sp.Rollback(), ShouldBeNil)
+		})
+
+		Convey("Generated keys are assumed to be returned", func() {
+			result, err := rds.NewMultiResult(plainDialect{rds.NewPostgres(conf)}, []string{"INSERT INTO a (name) VALUES ('one')"},
+				[]*rdsdata.ExecuteStatementOutput{{
+					NumberOfRecordsUpdated: 1,
+					GeneratedFields:        []types.Field{&types.FieldMemberLongValue{Value: 10}},
+				}})
+			So(err, ShouldBeNil)
+			lastInsertID, err := result.LastInsertId()
+			So(err, ShouldBeNil)
+			So(lastInsertID, ShouldEqual, 10)
+		})
+	})
+}
+
+// plainDialect implements none of the optional interfaces of the dialect it wraps, as external dialects may not.
+type plainDialect struct {
+	rds.Dialect
+}
+
+func FuzzDialectPostgres_MigrateQuery(f *testing.F) {
+	for _, query := range queryFixtures {
+		for _, literal := range literalFixtures {
+			f.Add(literal, query)
+		}
+	}
+	dialect := rds.NewPostgres(rds.NewConfig("resourceARN", "secretARN", "database", "region"))
+	args := []driver.NamedValue{{Ordinal: 1, Value: int64(1)}}
+	migrate := func(t *testing.T, query string) string {
+		input, err := dialect.MigrateQuery(query, args)
+		if err != nil {
+			t.Fatalf("%q: %v", query, err)
+		}
+		return aws.ToString(input.Sql)
+	}
+	// placeholders rewritten by the migration, which may only replace the $ of the placeholders $n with a colon.
+	placeholders := func(t *testing.T, query string, migrated string) int {
+		if len(query) != len(migrated) {
+			t.Fatalf("%q migrated to %q, which differs in length", query, migrated)
+		}
+		n := 0
+		for i := 0; i < len(query); i++ {
+			if query[i] == migrated[i] {
+				continue
+			}
+			if query[i] != '$' || migrated[i] != ':' || i+1 == len(query) || query[i+1] < '0' || query[i+1] > '9' {
+				t.Fatalf("%q migrated to %q, which differs at %d", query, migrated, i)
+			}
+			n++
+		}
+		return n
+	}
+
+	f.Fuzz(func(t *testing.T, literal string, query string) {
+		n := placeholders(t, query, migrate(t, query))
+		for _, quoted := range postgresLiterals(literal) {
+			migrated := migrate(t, quoted+" "+query)
+			if !strings.HasPrefix(migrated, quoted+" ") {
+				t.Fatalf("%q rewritten within its literal to %q", quoted+" "+query, migrated)
+			}
+			if placeholders(t, quoted+" "+query, migrated) != n {
+				t.Fatalf("%q counted the placeholders within its literal", quoted+" "+query)
+			}
+		}
+	})
 }
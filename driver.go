@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"database/sql/driver"
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/rdsdata"
 )
@@ -20,7 +21,16 @@ func NewDriver() *Driver {
 }
 
 // Driver implements the driver.Driver interface for RDS
-type Driver struct{}
+type Driver struct {
+	// Logger for connectors opened from a DSN.
+	Logger Logger
+	// LogLevels for connectors opened from a DSN.
+	LogLevels *LogLevels
+	// Hooks for connectors opened from a DSN.
+	Hooks Hooks
+	// Metrics for connectors opened from a DSN.
+	Metrics Metrics
+}
 
 // Open returns a new connection to the database.
 func (r *Driver) Open(name string) (driver.Conn, error) {
@@ -37,6 +47,10 @@ func (r *Driver) OpenConnector(dsn string) (driver.Connector, error) {
 	if err != nil {
 		return nil, err
 	}
+	conf.Logger = r.Logger
+	conf.LogLevels = r.LogLevels
+	conf.Hooks = r.Hooks
+	conf.Metrics = r.Metrics
 
 	awsConfig, err := config.LoadDefaultConfig(context.TODO(),
 		config.WithRegion(conf.AWSRegion))
@@ -45,7 +59,11 @@ func (r *Driver) OpenConnector(dsn string) (driver.Connector, error) {
 		return nil, err
 	}
 
-	client := rdsdata.NewFromConfig(awsConfig)
+	client := rdsdata.NewFromConfig(awsConfig, func(o *rdsdata.Options) {
+		if conf.EndpointURL != "" {
+			o.BaseEndpoint = aws.String(conf.EndpointURL)
+		}
+	})
 
 	return NewConnector(r, client, conf), nil
 }
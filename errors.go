@@ -1,6 +1,10 @@
 package rds
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
 
 // ErrNoMixedParams is thrown if parameters are mixed
 var ErrNoMixedParams = fmt.Errorf("please do not mix ordinal and named parameters")
@@ -10,3 +14,156 @@ var ErrClosed = fmt.Errorf("this connection is closed")
 
 // ErrInvalidDSNScheme for when the dsn doesn't match rds://
 var ErrInvalidDSNScheme = fmt.Errorf("this driver requires a DSN scheme of rds://")
+
+// ErrInvalidZeroDatePolicy for when the zero_date option isn't one of the supported policies
+var ErrInvalidZeroDatePolicy = fmt.Errorf("zero_date must be one of error, null, zero_time or string")
+
+// ErrInvalidTxKeepAlive for a tx_keepalive interval at which the Data API terminates idle transactions
+var ErrInvalidTxKeepAlive = fmt.Errorf("tx_keepalive must be shorter than %s", TransactionIdleTimeout)
+
+// ErrInvalidDate indicates that a zero or otherwise invalid date was returned by the database
+var ErrInvalidDate = fmt.Errorf("invalid date value")
+
+// ErrUnknownDialect for when the configured dialect hasn't been registered
+var ErrUnknownDialect = fmt.Errorf("unknown dialect")
+
+// ErrNoTransaction for when an operation requires a transaction, but none is open
+var ErrNoTransaction = fmt.Errorf("no transaction is open on this connection")
+
+// ErrLastInsertIDNotSupported for dialects which don't return generated keys, such as postgres
+var ErrLastInsertIDNotSupported = fmt.Errorf("LastInsertId is not supported by this dialect, use a RETURNING clause with capture_returning instead")
+
+// ErrInvalidSavepointName for savepoint names which aren't plain identifiers
+var ErrInvalidSavepointName = fmt.Errorf("savepoint names must be plain identifiers")
+
+// ErrTransactionExpired indicates that the Data API has terminated the transaction, either after
+// TransactionIdleTimeout without activity or after TransactionMaxLifetime
+var ErrTransactionExpired = fmt.Errorf("the transaction has expired")
+
+// ErrDeadlock indicates that the statement was rolled back to resolve a deadlock
+var ErrDeadlock = fmt.Errorf("deadlock detected")
+
+// ErrSerializationFailure indicates that a transaction could not be serialized with concurrent transactions
+var ErrSerializationFailure = fmt.Errorf("could not serialize transaction")
+
+// ErrLockTimeout indicates that a lock could not be acquired in time
+var ErrLockTimeout = fmt.Errorf("lock could not be acquired")
+
+// ErrCheckViolation indicates that a CHECK constraint was violated
+var ErrCheckViolation = fmt.Errorf("check constraint violated")
+
+// DatabaseError is an error returned by the Data API which the dialect has classified. Use
+// errors.Is with its Kind, such as ErrDeadlock, or errors.As to retrieve it.
+type DatabaseError struct {
+	// Kind of error, one of the sentinel errors above
+	Kind error
+	// Err as returned by the Data API
+	Err error
+}
+
+// Error message of the underlying error
+func (e *DatabaseError) Error() string {
+	return fmt.Sprintf("%v: %v", e.Kind, e.Err)
+}
+
+// Unwrap to both the Kind and the underlying error
+func (e *DatabaseError) Unwrap() []error {
+	return []error{e.Kind, e.Err}
+}
+
+// StatementError is returned when the Data API fails to execute a statement, recording the context
+// needed to correlate it with AWS support or CloudTrail. Use errors.As with the typed errors of the
+// dialect or the Data API, or errors.Is with their kinds, to inspect the underlying error.
+type StatementError struct {
+	// Query of the failed statement, as written
+	Query string
+	// Fingerprint of the query, as returned by FingerprintQuery
+	Fingerprint string
+	// Index of the statement, should the query have been split into several
+	Index int
+	// TransactionID the statement was executed in, if any
+	TransactionID string
+	// RequestID assigned by AWS to the failed request, if known
+	RequestID string
+	// Err returned by the Data API, as translated by the dialect
+	Err error
+}
+
+// Error message of the underlying error, followed by the statement's context
+func (e *StatementError) Error() string {
+	var details []string
+	if e.RequestID != "" {
+		details = append(details, "request_id="+e.RequestID)
+	}
+	if e.TransactionID != "" {
+		details = append(details, "transaction_id="+e.TransactionID)
+	}
+	details = append(details, fmt.Sprintf("statement=%d", e.Index), "fingerprint="+e.Fingerprint)
+	return fmt.Sprintf("%v [%s]", e.Err, strings.Join(details, " "))
+}
+
+// Unwrap to the error returned by the Data API
+func (e *StatementError) Unwrap() error {
+	return e.Err
+}
+
+// requestID assigned by AWS to the request which failed with the error, if any.
+func requestID(err error) string {
+	var responseErr interface{ ServiceRequestID() string }
+	if errors.As(err, &responseErr) {
+		return responseErr.ServiceRequestID()
+	}
+	return ""
+}
+
+// MultiStatementError is returned when split, atomically executed statements fail, either because one of
+// them failed and the transaction was rolled back, or because the transaction failed to commit.
+type MultiStatementError struct {
+	// Commit failed, after all statements succeeded
+	Commit bool
+	// RollbackErr returned when rolling back the transaction after a statement failed, if any
+	RollbackErr error
+	// Err returned by the commit, or by the failed statement as a *StatementError, which reports its index
+	// and query
+	Err error
+}
+
+// Error message, including whether the statements were rolled back
+func (e *MultiStatementError) Error() string {
+	switch {
+	case e.Commit:
+		return fmt.Sprintf("atomic statements failed to commit: %v", e.Err)
+	case e.RollbackErr != nil:
+		return fmt.Sprintf("atomic statements failed to roll back (%v): %v", e.RollbackErr, e.Err)
+	}
+	return fmt.Sprintf("atomic statements rolled back: %v", e.Err)
+}
+
+// Unwrap to the error returned by the failed statement or commit
+func (e *MultiStatementError) Unwrap() error {
+	return e.Err
+}
+
+// errorPattern maps a fragment of a Data API error message onto the kind of error it indicates.
+type errorPattern struct {
+	fragment string
+	kind     error
+}
+
+// classifyError wraps the error in a DatabaseError if its message matches any of the patterns.
+func classifyError(err error, patterns []errorPattern) error {
+	if err == nil {
+		return nil
+	}
+	var dbErr *DatabaseError
+	if errors.As(err, &dbErr) {
+		return err
+	}
+	message := strings.ToLower(err.Error())
+	for _, pattern := range patterns {
+		if strings.Contains(message, pattern.fragment) {
+			return &DatabaseError{Kind: pattern.kind, Err: err}
+		}
+	}
+	return err
+}
@@ -0,0 +1,27 @@
+package rds
+
+import "time"
+
+// SetLastActivity allows tests to simulate a transaction which has been idle.
+func (r *Tx) SetLastActivity(t time.Time) {
+	r.lastActivity.Store(t.UnixNano())
+}
+
+// SplitStatements of the query as connections of the dialect split it.
+func SplitStatements(dialect Dialect, query string) []string {
+	return syntaxOf(dialect).split(query)
+}
+
+// UnregisterDialect allows tests to remove the dialects they registered.
+func UnregisterDialect(name string) {
+	unregisterDialect(name)
+}
+
+// DetectDialect allows tests to check which dialect a version is detected as.
+func DetectDialect(version string, conf *Config) (Dialect, bool) {
+	factory, ok := detectDialect(version)
+	if !ok {
+		return nil, false
+	}
+	return factory(conf), true
+}
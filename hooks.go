@@ -0,0 +1,141 @@
+package rds
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/rdsdata"
+)
+
+var _ Hooks = NoopHooks{} // explicit compile time type check
+
+// Hooks are called around every request the driver makes to the Data API, such as to trace them,
+// propagate request IDs, or tag queries. Each Before hook returns the context used for the request,
+// which is in turn passed to the matching After hook.
+type Hooks interface {
+	// BeforeStatement is called before a statement is executed. Its input may be modified.
+	BeforeStatement(ctx context.Context, event *StatementEvent) context.Context
+	// AfterStatement is called once a statement has executed, with its output if it succeeded.
+	AfterStatement(ctx context.Context, event *StatementEvent, err error)
+	// BeforeTransaction is called before a transaction is begun, committed or rolled back.
+	BeforeTransaction(ctx context.Context, event *TransactionEvent) context.Context
+	// AfterTransaction is called once a transaction was begun, committed or rolled back.
+	AfterTransaction(ctx context.Context, event *TransactionEvent, err error)
+	// BeforeWakeup is called before the connector wakes up the cluster and detects its dialect.
+	BeforeWakeup(ctx context.Context, event *WakeupEvent) context.Context
+	// AfterWakeup is called once the cluster is awake, or the connector gave up waking it.
+	AfterWakeup(ctx context.Context, event *WakeupEvent, err error)
+}
+
+// StatementEvent describes a statement executed against the Data API.
+type StatementEvent struct {
+	// Dialect of the connection executing the statement
+	Dialect Dialect
+	// Input to ExecuteStatement, including the migrated SQL and the transaction ID, if any
+	Input *rdsdata.ExecuteStatementInput
+	// Output of ExecuteStatement, set once the statement executed successfully
+	Output *rdsdata.ExecuteStatementOutput
+}
+
+// TransactionOp is an operation on a transaction.
+type TransactionOp string
+
+const (
+	// TransactionBegin of a new transaction
+	TransactionBegin TransactionOp = "BEGIN"
+	// TransactionCommit of an open transaction
+	TransactionCommit TransactionOp = "COMMIT"
+	// TransactionRollback of an open transaction
+	TransactionRollback TransactionOp = "ROLLBACK"
+)
+
+// TransactionEvent describes an operation on a transaction.
+type TransactionEvent struct {
+	// Op performed on the transaction
+	Op TransactionOp
+	// Dialect of the connection the transaction is open on
+	Dialect Dialect
+	// ResourceArn of the cluster
+	ResourceArn string
+	// Database the transaction is open on
+	Database string
+	// TransactionID, set once the transaction was begun
+	TransactionID string
+	// Status returned by the Data API once the transaction was committed or rolled back
+	Status string
+}
+
+// WakeupEvent describes the wakeup of a cluster.
+type WakeupEvent struct {
+	// ResourceArn of the cluster
+	ResourceArn string
+	// Database being connected to
+	Database string
+	// Attempts made at waking up the cluster
+	Attempts int
+	// Version reported by the cluster once awake
+	Version string
+	// Dialect detected, or configured, once awake
+	Dialect Dialect
+}
+
+// NoopHooks does nothing, and may be embedded to implement only some of the Hooks.
+type NoopHooks struct{}
+
+// BeforeStatement does nothing.
+func (NoopHooks) BeforeStatement(ctx context.Context, _ *StatementEvent) context.Context {
+	return ctx
+}
+
+// AfterStatement does nothing.
+func (NoopHooks) AfterStatement(context.Context, *StatementEvent, error) {}
+
+// BeforeTransaction does nothing.
+func (NoopHooks) BeforeTransaction(ctx context.Context, _ *TransactionEvent) context.Context {
+	return ctx
+}
+
+// AfterTransaction does nothing.
+func (NoopHooks) AfterTransaction(context.Context, *TransactionEvent, error) {}
+
+// BeforeWakeup does nothing.
+func (NoopHooks) BeforeWakeup(ctx context.Context, _ *WakeupEvent) context.Context {
+	return ctx
+}
+
+// AfterWakeup does nothing.
+func (NoopHooks) AfterWakeup(context.Context, *WakeupEvent, error) {}
+
+// hooksOrNoop returns the configured hooks, or NoopHooks if none are.
+func hooksOrNoop(hooks Hooks) Hooks {
+	if hooks == nil {
+		return NoopHooks{}
+	}
+	return hooks
+}
+
+// executeStatement against the Data API, calling the statement hooks around it.
+func (r *Connection) executeStatement(ctx context.Context, input *rdsdata.ExecuteStatementInput) (*rdsdata.ExecuteStatementOutput, error) {
+	event := &StatementEvent{Dialect: r.dialect, Input: input}
+	ctx = r.hooks.BeforeStatement(ctx, event)
+	start := time.Now()
+	output, err := r.rds.ExecuteStatement(ctx, event.Input)
+	r.metrics.ObserveCall(CallExecuteStatement, time.Since(start), err)
+	if output != nil {
+		r.metrics.ObserveResult(len(output.Records), resultSize(output))
+	}
+	event.Output = output
+	r.hooks.AfterStatement(ctx, event, err)
+	return output, err
+}
+
+// transactionEvent for an operation on the passed transaction ID.
+func (r *Connection) transactionEvent(op TransactionOp, transactionID string) *TransactionEvent {
+	return &TransactionEvent{
+		Op:            op,
+		Dialect:       r.dialect,
+		ResourceArn:   r.resourceARN,
+		Database:      r.database,
+		TransactionID: transactionID,
+	}
+}
@@ -0,0 +1,92 @@
+package rds_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rdsdata"
+	"github.com/golang/mock/gomock"
+	"github.com/krotscheck/go-rds-driver"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type hookKey struct{}
+
+// recordingHooks tags every statement, and records the name of each hook called.
+type recordingHooks struct {
+	rds.NoopHooks
+	calls []string
+}
+
+func (h *recordingHooks) BeforeStatement(ctx context.Context, event *rds.StatementEvent) context.Context {
+	h.calls = append(h.calls, "BeforeStatement")
+	event.Input.Sql = aws.String("/* service=accounts */ " + aws.ToString(event.Input.Sql))
+	return context.WithValue(ctx, hookKey{}, "statement")
+}
+
+func (h *recordingHooks) AfterStatement(ctx context.Context, event *rds.StatementEvent, err error) {
+	h.calls = append(h.calls, "AfterStatement:"+ctx.Value(hookKey{}).(string))
+}
+
+func (h *recordingHooks) BeforeTransaction(ctx context.Context, event *rds.TransactionEvent) context.Context {
+	h.calls = append(h.calls, "BeforeTransaction:"+string(event.Op))
+	return context.WithValue(ctx, hookKey{}, event.TransactionID)
+}
+
+func (h *recordingHooks) AfterTransaction(ctx context.Context, event *rds.TransactionEvent, err error) {
+	h.calls = append(h.calls, "AfterTransaction:"+ctx.Value(hookKey{}).(string)+":"+event.TransactionID+":"+event.Status)
+}
+
+func (h *recordingHooks) BeforeWakeup(ctx context.Context, event *rds.WakeupEvent) context.Context {
+	h.calls = append(h.calls, "BeforeWakeup")
+	return ctx
+}
+
+func (h *recordingHooks) AfterWakeup(ctx context.Context, event *rds.WakeupEvent, err error) {
+	h.calls = append(h.calls, "AfterWakeup:"+event.Version)
+}
+
+func Test_Hooks(t *testing.T) {
+	ctx := context.Background()
+
+	Convey("Hooks", t, func() {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		mockRDS := NewMockAWSClientInterface(ctrl)
+		hooks := &recordingHooks{}
+		conf := rds.NewConfig("resourceARN", "secretARN", "database", "region")
+		conf.Hooks = hooks
+
+		Convey("Wakeup", func() {
+			ExpectWakeup(mockRDS, conf)
+			_, err := rds.NewConnector(rds.NewDriver(), mockRDS, conf).Wakeup()
+			So(err, ShouldBeNil)
+			So(hooks.calls, ShouldResemble, []string{"BeforeWakeup", "AfterWakeup:5.7.0"})
+		})
+
+		Convey("Statements and transactions", func() {
+			conn := rds.NewConnection(ctx, mockRDS, conf, rds.NewMySQL(conf)).(*rds.Connection)
+			ExpectBeginTransaction(mockRDS, "transactionID")
+			ExpectStatement(mockRDS, "/* service=accounts */ SELECT 1").Return(&rdsdata.ExecuteStatementOutput{}, nil)
+			mockRDS.EXPECT().CommitTransaction(gomock.Any(), gomock.Any()).
+				Return(&rdsdata.CommitTransactionOutput{TransactionStatus: aws.String("Transaction Committed")}, nil)
+
+			tx, err := conn.BeginTx(ctx, driver.TxOptions{})
+			So(err, ShouldBeNil)
+			_, err = conn.ExecContext(ctx, "SELECT 1", nil)
+			So(err, ShouldBeNil)
+			So(tx.Commit(), ShouldBeNil)
+
+			So(hooks.calls, ShouldResemble, []string{
+				"BeforeTransaction:BEGIN",
+				"AfterTransaction::transactionID:",
+				"BeforeStatement",
+				"AfterStatement:statement",
+				"BeforeTransaction:COMMIT",
+				"AfterTransaction:transactionID:transactionID:Transaction Committed",
+			})
+		})
+	})
+}
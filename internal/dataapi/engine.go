@@ -0,0 +1,286 @@
+// Package dataapi emulates the Data API on top of a database/sql database, converting its parameters,
+// records, metadata and transactions. It's shared by the rdstest fake and the rds-data-local server.
+package dataapi
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rdsdata"
+	"github.com/aws/aws-sdk-go-v2/service/rdsdata/types"
+)
+
+// Transaction statuses returned by the Data API.
+const (
+	StatusCommitted  = "Transaction Committed"
+	StatusRolledBack = "Rollback Complete"
+)
+
+// NewEngine executing statements against the database.
+func NewEngine(db *sql.DB) *Engine {
+	return &Engine{
+		DB:           db,
+		transactions: map[string]*sql.Tx{},
+	}
+}
+
+// Engine executing Data API requests. Errors are returned as the exceptions of the Data API, such as
+// *types.BadRequestException, other than context errors, which are returned as is.
+type Engine struct {
+	// DB the statements are executed against.
+	DB *sql.DB
+	// Postgres emulates the PostgreSQL Data API, rather than MySQL's.
+	Postgres bool
+	// NumberedPlaceholders such as $1, rather than ?, are used to bind parameters.
+	NumberedPlaceholders bool
+	// Intercept answers statements which the database doesn't support, before they're executed.
+	Intercept func(query string) (*rdsdata.ExecuteStatementOutput, bool)
+	// Message of an error returned by the database, as worded by the Data API. Defaults to the error's message.
+	Message func(err error) string
+
+	mu           sync.Mutex
+	transactions map[string]*sql.Tx
+}
+
+// queryer is satisfied by both the database and its transactions.
+type queryer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// OpenTransactions which have been begun but neither committed nor rolled back.
+func (e *Engine) OpenTransactions() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return len(e.transactions)
+}
+
+// Close rolls back any open transactions. The database is left open.
+func (e *Engine) Close() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for id, tx := range e.transactions {
+		_ = tx.Rollback()
+		delete(e.transactions, id)
+	}
+}
+
+// ExecuteStatement against the database, in the given transaction if any.
+func (e *Engine) ExecuteStatement(ctx context.Context, input *rdsdata.ExecuteStatementInput) (*rdsdata.ExecuteStatementOutput, error) {
+	if err := validate(input.ResourceArn, input.SecretArn); err != nil {
+		return nil, err
+	}
+	runner, err := e.runner(input.TransactionId)
+	if err != nil {
+		return nil, err
+	}
+	// Records formatted as JSON are keyed by the column labels, so the metadata is always needed.
+	output, err := e.execute(ctx, runner, aws.ToString(input.Sql), input.Parameters, true)
+	if err != nil {
+		return nil, err
+	}
+	if input.FormatRecordsAs == types.RecordsFormatTypeJson && output.Records != nil {
+		if err := formatRecords(output); err != nil {
+			return nil, err
+		}
+	}
+	if !input.IncludeResultMetadata {
+		output.ColumnMetadata = nil
+	}
+	return output, nil
+}
+
+// BatchExecuteStatement once for every set of parameters, in the given transaction if any.
+func (e *Engine) BatchExecuteStatement(ctx context.Context, input *rdsdata.BatchExecuteStatementInput) (*rdsdata.BatchExecuteStatementOutput, error) {
+	if err := validate(input.ResourceArn, input.SecretArn); err != nil {
+		return nil, err
+	}
+	runner, err := e.runner(input.TransactionId)
+	if err != nil {
+		return nil, err
+	}
+	parameterSets := input.ParameterSets
+	if len(parameterSets) == 0 {
+		parameterSets = [][]types.SqlParameter{nil}
+	}
+	output := &rdsdata.BatchExecuteStatementOutput{UpdateResults: []types.UpdateResult{}}
+	for _, params := range parameterSets {
+		out, err := e.execute(ctx, runner, aws.ToString(input.Sql), params, false)
+		if err != nil {
+			return nil, err
+		}
+		output.UpdateResults = append(output.UpdateResults, types.UpdateResult{GeneratedFields: out.GeneratedFields})
+	}
+	return output, nil
+}
+
+// BeginTransaction returning the ID to pass to subsequent statements.
+func (e *Engine) BeginTransaction(_ context.Context, input *rdsdata.BeginTransactionInput) (*rdsdata.BeginTransactionOutput, error) {
+	if err := validate(input.ResourceArn, input.SecretArn); err != nil {
+		return nil, err
+	}
+	// Transactions outlive the request which began them.
+	tx, err := e.DB.BeginTx(context.Background(), nil)
+	if err != nil {
+		return nil, e.databaseError(err)
+	}
+
+	id := NewID(48)
+	e.mu.Lock()
+	e.transactions[id] = tx
+	e.mu.Unlock()
+	return &rdsdata.BeginTransactionOutput{TransactionId: aws.String(id)}, nil
+}
+
+// CommitTransaction with the given ID.
+func (e *Engine) CommitTransaction(_ context.Context, input *rdsdata.CommitTransactionInput) (*rdsdata.CommitTransactionOutput, error) {
+	if err := validate(input.ResourceArn, input.SecretArn); err != nil {
+		return nil, err
+	}
+	tx, err := e.finish(input.TransactionId)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, e.databaseError(err)
+	}
+	return &rdsdata.CommitTransactionOutput{TransactionStatus: aws.String(StatusCommitted)}, nil
+}
+
+// RollbackTransaction with the given ID.
+func (e *Engine) RollbackTransaction(_ context.Context, input *rdsdata.RollbackTransactionInput) (*rdsdata.RollbackTransactionOutput, error) {
+	if err := validate(input.ResourceArn, input.SecretArn); err != nil {
+		return nil, err
+	}
+	tx, err := e.finish(input.TransactionId)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Rollback(); err != nil {
+		return nil, e.databaseError(err)
+	}
+	return &rdsdata.RollbackTransactionOutput{TransactionStatus: aws.String(StatusRolledBack)}, nil
+}
+
+// execute a single statement with the given parameters.
+func (e *Engine) execute(ctx context.Context, runner queryer, query string, params []types.SqlParameter, includeMetadata bool) (*rdsdata.ExecuteStatementOutput, error) {
+	if strings.TrimSpace(query) == "" {
+		return nil, &types.BadRequestException{Message: aws.String("SQL statement is required")}
+	}
+	if e.Intercept != nil {
+		if output, ok := e.Intercept(query); ok {
+			return output, nil
+		}
+	}
+
+	query, args, err := bindParameters(query, params, e.NumberedPlaceholders)
+	if err != nil {
+		return nil, &types.BadRequestException{Message: aws.String(err.Error())}
+	}
+
+	if returnsRows(query) {
+		rows, err := runner.QueryContext(ctx, query, args...)
+		if err != nil {
+			return nil, e.databaseError(err)
+		}
+		defer rows.Close()
+		output, err := e.records(rows, includeMetadata)
+		if err != nil {
+			return nil, e.databaseError(err)
+		}
+		// The rows returned by modifying statements are those which were updated.
+		switch firstKeyword(query) {
+		case "INSERT", "UPDATE", "DELETE", "REPLACE":
+			output.NumberOfRecordsUpdated = int64(len(output.Records))
+		}
+		return output, nil
+	}
+
+	result, err := runner.ExecContext(ctx, query, args...)
+	if err != nil {
+		return nil, e.databaseError(err)
+	}
+	output := &rdsdata.ExecuteStatementOutput{}
+	output.NumberOfRecordsUpdated, _ = result.RowsAffected()
+	// The MySQL Data API returns the AUTO_INCREMENT value of inserted rows, PostgreSQL requires RETURNING.
+	if !e.Postgres && output.NumberOfRecordsUpdated > 0 && firstKeyword(query) == "INSERT" {
+		if id, err := result.LastInsertId(); err == nil && id > 0 {
+			output.GeneratedFields = []types.Field{&types.FieldMemberLongValue{Value: id}}
+		}
+	}
+	return output, nil
+}
+
+// runner of statements in the given transaction, or the database if there's none.
+func (e *Engine) runner(transactionID *string) (queryer, error) {
+	id := aws.ToString(transactionID)
+	if id == "" {
+		return e.DB, nil
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	tx, ok := e.transactions[id]
+	if !ok {
+		return nil, transactionNotFound(id)
+	}
+	return tx, nil
+}
+
+// finish the transaction with the given ID, removing it from the open transactions.
+func (e *Engine) finish(transactionID *string) (*sql.Tx, error) {
+	id := aws.ToString(transactionID)
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	tx, ok := e.transactions[id]
+	if !ok {
+		return nil, transactionNotFound(id)
+	}
+	delete(e.transactions, id)
+	return tx, nil
+}
+
+// databaseError returned by the database, as a BadRequestException worded by Message.
+func (e *Engine) databaseError(err error) error {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+	message := err.Error()
+	if e.Message != nil {
+		message = e.Message(err)
+	}
+	return &types.BadRequestException{Message: aws.String(message)}
+}
+
+// validate the ARNs which the Data API requires on every request.
+func validate(resourceARN, secretARN *string) error {
+	if aws.ToString(resourceARN) == "" || aws.ToString(secretARN) == "" {
+		return &types.BadRequestException{Message: aws.String("ResourceArn and SecretArn are required")}
+	}
+	return nil
+}
+
+// transactionNotFound for a transaction which isn't open.
+func transactionNotFound(id string) error {
+	return &types.TransactionNotFoundException{Message: aws.String(fmt.Sprintf("Transaction %s is not found", id))}
+}
+
+// NewID of the given length, such as for transactions.
+func NewID(length int) string {
+	buf := make([]byte, length*3/4)
+	_, _ = rand.Read(buf)
+	return base64.RawURLEncoding.EncodeToString(buf)
+}
+
+// NewRequestID in the format of the IDs AWS assigns to requests.
+func NewRequestID() string {
+	buf := make([]byte, 16)
+	_, _ = rand.Read(buf)
+	return fmt.Sprintf("%x-%x-%x-%x-%x", buf[0:4], buf[4:6], buf[6:8], buf[8:10], buf[10:])
+}
@@ -0,0 +1,458 @@
+package dataapi
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rdsdata"
+	"github.com/aws/aws-sdk-go-v2/service/rdsdata/types"
+)
+
+// returningClause makes a modifying statement return rows.
+var returningClause = regexp.MustCompile(`(?i)\breturning\b`)
+
+// declaredType splits a column's type into its name and precision, such as VARCHAR(255).
+var declaredType = regexp.MustCompile(`^(UNSIGNED )?([^(]*?)\s*(?:\(\s*(\d+)\s*(?:,\s*(\d+)\s*)?\))?\s*(UNSIGNED)?$`)
+
+// kind of value held by a column, which decides the field it's returned as.
+type kind int
+
+const (
+	kindText kind = iota
+	kindInteger
+	kindFloat
+	kindDecimal
+	kindBoolean
+	kindBinary
+	kindDate
+	kindTime
+	kindTimestamp
+	kindYear
+)
+
+// columnType as reported by the emulated databases, with its java.sql.Types code.
+type columnType struct {
+	mysql    string
+	postgres string
+	jdbc     int32
+	kind     kind
+}
+
+// columnTypes by the names the databases report, or SQLite accepts in declarations. Undeclared
+// columns, such as expressions in SQLite, are typed by their first value.
+var columnTypes = map[string]columnType{
+	"NULL":              {"NULL", "unknown", 0, kindText},
+	"BOOL":              {"TINYINT", "bool", -7, kindBoolean},
+	"BOOLEAN":           {"TINYINT", "bool", -7, kindBoolean},
+	"BIT":               {"BIT", "bit", -7, kindBinary},
+	"TINYINT":           {"TINYINT", "int2", -6, kindInteger},
+	"SMALLINT":          {"SMALLINT", "int2", 5, kindInteger},
+	"INT2":              {"SMALLINT", "int2", 5, kindInteger},
+	"MEDIUMINT":         {"MEDIUMINT", "int4", 4, kindInteger},
+	"INT":               {"INT", "int4", 4, kindInteger},
+	"INTEGER":           {"INT", "int4", 4, kindInteger},
+	"INT4":              {"INT", "int4", 4, kindInteger},
+	"BIGINT":            {"BIGINT", "int8", -5, kindInteger},
+	"INT8":              {"BIGINT", "int8", -5, kindInteger},
+	"YEAR":              {"YEAR", "int2", 91, kindYear},
+	"FLOAT":             {"FLOAT", "float4", 7, kindFloat},
+	"FLOAT4":            {"FLOAT", "float4", 7, kindFloat},
+	"REAL":              {"DOUBLE", "float4", 7, kindFloat},
+	"DOUBLE":            {"DOUBLE", "float8", 8, kindFloat},
+	"DOUBLE PRECISION":  {"DOUBLE", "float8", 8, kindFloat},
+	"FLOAT8":            {"DOUBLE", "float8", 8, kindFloat},
+	"DECIMAL":           {"DECIMAL", "numeric", 3, kindDecimal},
+	"NUMERIC":           {"DECIMAL", "numeric", 2, kindDecimal},
+	"CHAR":              {"CHAR", "bpchar", 1, kindText},
+	"BPCHAR":            {"CHAR", "bpchar", 1, kindText},
+	"VARCHAR":           {"VARCHAR", "varchar", 12, kindText},
+	"CHARACTER VARYING": {"VARCHAR", "varchar", 12, kindText},
+	"TINYTEXT":          {"TINYTEXT", "text", 12, kindText},
+	"TEXT":              {"TEXT", "text", 12, kindText},
+	"MEDIUMTEXT":        {"MEDIUMTEXT", "text", 12, kindText},
+	"LONGTEXT":          {"LONGTEXT", "text", 12, kindText},
+	"ENUM":              {"ENUM", "varchar", 1, kindText},
+	"SET":               {"SET", "varchar", 1, kindText},
+	"BINARY":            {"BINARY", "bytea", -2, kindBinary},
+	"VARBINARY":         {"VARBINARY", "bytea", -3, kindBinary},
+	"TINYBLOB":          {"TINYBLOB", "bytea", -3, kindBinary},
+	"BLOB":              {"BLOB", "bytea", -4, kindBinary},
+	"MEDIUMBLOB":        {"MEDIUMBLOB", "bytea", -4, kindBinary},
+	"LONGBLOB":          {"LONGBLOB", "bytea", -4, kindBinary},
+	"BYTEA":             {"BLOB", "bytea", -2, kindBinary},
+	"GEOMETRY":          {"GEOMETRY", "geometry", -2, kindBinary},
+	"DATE":              {"DATE", "date", 91, kindDate},
+	"TIME":              {"TIME", "time", 92, kindTime},
+	"DATETIME":          {"DATETIME", "timestamp", 93, kindTimestamp},
+	"TIMESTAMP":         {"TIMESTAMP", "timestamp", 93, kindTimestamp},
+	"TIMESTAMPTZ":       {"TIMESTAMP", "timestamptz", 93, kindTimestamp},
+	"JSON":              {"JSON", "json", 1111, kindText},
+	"JSONB":             {"JSON", "jsonb", 1111, kindText},
+	"UUID":              {"CHAR", "uuid", 1111, kindText},
+}
+
+// column of a result set, with the kind of its values.
+type column struct {
+	metadata types.ColumnMetadata
+	kind     kind
+}
+
+// records returned by the query, converted to the fields of the Data API.
+func (e *Engine) records(rows *sql.Rows, includeMetadata bool) (*rdsdata.ExecuteStatementOutput, error) {
+	described, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, err
+	}
+	columns := make([]column, len(described))
+	for i, ct := range described {
+		nullable, ok := ct.Nullable()
+		columns[i] = e.column(ct.Name(), ct.DatabaseTypeName(), nullable || !ok)
+		if precision, scale, ok := ct.DecimalSize(); ok {
+			columns[i].metadata.Precision, columns[i].metadata.Scale = int32(precision), int32(scale)
+		} else if length, ok := ct.Length(); ok && length > 0 && length <= 1<<31-1 {
+			columns[i].metadata.Precision = int32(length)
+		}
+	}
+
+	output := &rdsdata.ExecuteStatementOutput{Records: [][]types.Field{}}
+	values := make([]any, len(columns))
+	dest := make([]any, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		record := make([]types.Field, len(values))
+		for i, value := range values {
+			if aws.ToString(columns[i].metadata.TypeName) == "" && value != nil {
+				columns[i] = e.column(described[i].Name(), inferredType(value), true)
+			}
+			record[i] = e.field(columns[i].kind, value)
+		}
+		output.Records = append(output.Records, record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if includeMetadata {
+		output.ColumnMetadata = make([]types.ColumnMetadata, len(columns))
+		for i, c := range columns {
+			if aws.ToString(c.metadata.TypeName) == "" {
+				c = e.column(described[i].Name(), inferredType(nil), true)
+			}
+			output.ColumnMetadata[i] = c.metadata
+		}
+	}
+	return output, nil
+}
+
+// Column metadata of a column with the given type, as reported by the database.
+func (e *Engine) Column(name string, typeName string, nullable bool) types.ColumnMetadata {
+	return e.column(name, typeName, nullable).metadata
+}
+
+// column with the given type, as reported by the database or declared in SQLite.
+func (e *Engine) column(name string, typeName string, nullable bool) column {
+	c := column{metadata: types.ColumnMetadata{
+		Name:     aws.String(name),
+		Label:    aws.String(name),
+		TypeName: aws.String(""),
+	}}
+	if nullable {
+		c.metadata.Nullable = 1
+	}
+	match := declaredType.FindStringSubmatch(strings.ToUpper(strings.Join(strings.Fields(typeName), " ")))
+	if match == nil || match[2] == "" {
+		return c
+	}
+	precision, _ := strconv.ParseInt(match[3], 10, 32)
+	scale, _ := strconv.ParseInt(match[4], 10, 32)
+	c.metadata.Precision = int32(precision)
+	c.metadata.Scale = int32(scale)
+
+	known, ok := columnTypes[match[2]]
+	if !ok {
+		known = columnType{mysql: match[2], postgres: strings.ToLower(match[2]), jdbc: 1111}
+	}
+	unsigned := match[1] != "" || match[5] != ""
+	c.kind = known.kind
+	c.metadata.Type = known.jdbc
+	c.metadata.IsSigned = known.kind == kindInteger && !unsigned
+	c.metadata.TypeName = aws.String(known.mysql)
+	if e.Postgres {
+		c.metadata.TypeName = aws.String(known.postgres)
+	} else if unsigned {
+		c.metadata.TypeName = aws.String(known.mysql + " UNSIGNED")
+	}
+	return c
+}
+
+// field of a record holding a value of the given kind.
+func (e *Engine) field(k kind, value any) types.Field {
+	switch v := value.(type) {
+	case nil:
+		return &types.FieldMemberIsNull{Value: true}
+	case int64:
+		return e.integerField(k, v)
+	case uint64:
+		return e.integerField(k, int64(v))
+	case float64:
+		if k == kindDecimal {
+			return &types.FieldMemberStringValue{Value: strconv.FormatFloat(v, 'f', -1, 64)}
+		}
+		return &types.FieldMemberDoubleValue{Value: v}
+	case bool:
+		return &types.FieldMemberBooleanValue{Value: v}
+	case string:
+		return e.textField(k, v)
+	case []byte:
+		if k == kindBinary {
+			return &types.FieldMemberBlobValue{Value: v}
+		}
+		return e.textField(k, string(v))
+	case time.Time:
+		switch k {
+		case kindDate:
+			return &types.FieldMemberStringValue{Value: v.Format("2006-01-02")}
+		case kindTime:
+			return &types.FieldMemberStringValue{Value: v.Format("15:04:05.999999")}
+		}
+		return &types.FieldMemberStringValue{Value: v.UTC().Format("2006-01-02 15:04:05.999999")}
+	}
+	return &types.FieldMemberStringValue{Value: fmt.Sprint(value)}
+}
+
+// integerField holding a value of the given kind.
+func (e *Engine) integerField(k kind, v int64) types.Field {
+	switch {
+	case k == kindBoolean && e.Postgres:
+		return &types.FieldMemberBooleanValue{Value: v != 0}
+	case k == kindDecimal:
+		return &types.FieldMemberStringValue{Value: strconv.FormatInt(v, 10)}
+	case k == kindYear:
+		return &types.FieldMemberStringValue{Value: fmt.Sprintf("%04d-01-01", v)}
+	}
+	return &types.FieldMemberLongValue{Value: v}
+}
+
+// textField holding a value of the given kind, as returned in the text protocol of the database.
+func (e *Engine) textField(k kind, v string) types.Field {
+	switch k {
+	case kindInteger:
+		if i, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return &types.FieldMemberLongValue{Value: i}
+		}
+	case kindFloat:
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return &types.FieldMemberDoubleValue{Value: f}
+		}
+	case kindBoolean:
+		if b, err := strconv.ParseBool(v); err == nil && e.Postgres {
+			return &types.FieldMemberBooleanValue{Value: b}
+		} else if i, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return &types.FieldMemberLongValue{Value: i}
+		}
+	case kindYear:
+		if len(v) == 4 {
+			return &types.FieldMemberStringValue{Value: v + "-01-01"}
+		}
+	}
+	return &types.FieldMemberStringValue{Value: v}
+}
+
+// inferredType of an undeclared column, from its value.
+func inferredType(value any) string {
+	switch value.(type) {
+	case int64:
+		return "BIGINT"
+	case float64:
+		return "DOUBLE"
+	case bool:
+		return "BOOLEAN"
+	case string:
+		return "VARCHAR"
+	case []byte:
+		return "BLOB"
+	case time.Time:
+		return "DATETIME"
+	}
+	return "NULL"
+}
+
+// formatRecords as a JSON array of objects keyed by the column labels, replacing the records.
+func formatRecords(output *rdsdata.ExecuteStatementOutput) error {
+	records := make([]map[string]any, len(output.Records))
+	for i, record := range output.Records {
+		records[i] = map[string]any{}
+		for j, field := range record {
+			var value any
+			switch v := field.(type) {
+			case *types.FieldMemberStringValue:
+				value = v.Value
+			case *types.FieldMemberLongValue:
+				value = v.Value
+			case *types.FieldMemberDoubleValue:
+				value = v.Value
+			case *types.FieldMemberBooleanValue:
+				value = v.Value
+			case *types.FieldMemberBlobValue:
+				value = v.Value
+			}
+			records[i][aws.ToString(output.ColumnMetadata[j].Label)] = value
+		}
+	}
+	formatted, err := json.Marshal(records)
+	if err != nil {
+		return err
+	}
+	output.FormattedRecords = aws.String(string(formatted))
+	output.Records = nil
+	return nil
+}
+
+// bindParameters replaces the named placeholders of the Data API, such as :id, with positional
+// placeholders, returning the values to bind them to. Numbered placeholders, such as $1, are
+// reused for repeated parameters.
+func bindParameters(query string, params []types.SqlParameter, numbered bool) (string, []any, error) {
+	values := make(map[string]any, len(params))
+	for _, param := range params {
+		value, err := parameterValue(param.Value)
+		if err != nil {
+			return "", nil, fmt.Errorf("parameter %s: %w", aws.ToString(param.Name), err)
+		}
+		values[aws.ToString(param.Name)] = value
+	}
+
+	var b strings.Builder
+	var args []any
+	positions := map[string]int{}
+	for i := 0; i < len(query); {
+		c := query[i]
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			end := strings.IndexByte(query[i+1:], c)
+			if end < 0 {
+				end = len(query)
+			} else {
+				end += i + 2
+			}
+			b.WriteString(query[i:end])
+			i = end
+		case strings.HasPrefix(query[i:], "--"):
+			end := strings.IndexByte(query[i:], '\n')
+			if end < 0 {
+				end = len(query)
+			} else {
+				end += i
+			}
+			b.WriteString(query[i:end])
+			i = end
+		case strings.HasPrefix(query[i:], "/*"):
+			end := strings.Index(query[i:], "*/")
+			if end < 0 {
+				end = len(query)
+			} else {
+				end += i + 2
+			}
+			b.WriteString(query[i:end])
+			i = end
+		case strings.HasPrefix(query[i:], "::"):
+			b.WriteString("::")
+			i += 2
+		case c == ':' && i+1 < len(query) && isNameByte(query[i+1]):
+			end := i + 1
+			for end < len(query) && isNameByte(query[end]) {
+				end++
+			}
+			name := query[i+1 : end]
+			value, ok := values[name]
+			if !ok {
+				return "", nil, fmt.Errorf("No value specified for parameter %s", name)
+			}
+			if !numbered {
+				args = append(args, value)
+				b.WriteByte('?')
+			} else if position, ok := positions[name]; ok {
+				fmt.Fprintf(&b, "$%d", position)
+			} else {
+				args = append(args, value)
+				positions[name] = len(args)
+				fmt.Fprintf(&b, "$%d", len(args))
+			}
+			i = end
+		default:
+			b.WriteByte(c)
+			i++
+		}
+	}
+	return b.String(), args, nil
+}
+
+// parameterValue to bind for a parameter of the Data API.
+func parameterValue(field types.Field) (any, error) {
+	switch v := field.(type) {
+	case nil, *types.FieldMemberIsNull:
+		return nil, nil
+	case *types.FieldMemberStringValue:
+		return v.Value, nil
+	case *types.FieldMemberLongValue:
+		return v.Value, nil
+	case *types.FieldMemberDoubleValue:
+		return v.Value, nil
+	case *types.FieldMemberBooleanValue:
+		return v.Value, nil
+	case *types.FieldMemberBlobValue:
+		return v.Value, nil
+	}
+	return nil, fmt.Errorf("unsupported parameter type %T", field)
+}
+
+func isNameByte(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// firstKeyword of the query, skipping comments and parentheses.
+func firstKeyword(query string) string {
+	for {
+		query = strings.TrimLeft(query, " \t\r\n(")
+		switch {
+		case strings.HasPrefix(query, "--"):
+			if end := strings.IndexByte(query, '\n'); end >= 0 {
+				query = query[end:]
+				continue
+			}
+			return ""
+		case strings.HasPrefix(query, "/*"):
+			if end := strings.Index(query, "*/"); end >= 0 {
+				query = query[end+2:]
+				continue
+			}
+			return ""
+		}
+		end := strings.IndexFunc(query, func(r rune) bool {
+			return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z')
+		})
+		if end < 0 {
+			end = len(query)
+		}
+		return strings.ToUpper(query[:end])
+	}
+}
+
+// returnsRows if the query is a query, or a modifying statement with a RETURNING clause.
+func returnsRows(query string) bool {
+	switch firstKeyword(query) {
+	case "SELECT", "WITH", "VALUES", "SHOW", "PRAGMA", "EXPLAIN":
+		return true
+	}
+	return returningClause.MatchString(query)
+}
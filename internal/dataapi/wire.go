@@ -0,0 +1,245 @@
+package dataapi
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rdsdata/types"
+)
+
+// The JSON documents of the Data API's REST protocol, as sent and expected by the AWS SDKs. The SDK's
+// types can't be marshalled themselves, as their unions are interfaces.
+
+// Field of a record or parameter, of which exactly one member is set.
+type Field struct {
+	ArrayValue   *ArrayValue `json:"arrayValue,omitempty"`
+	BlobValue    *[]byte     `json:"blobValue,omitempty"`
+	BooleanValue *bool       `json:"booleanValue,omitempty"`
+	DoubleValue  *float64    `json:"doubleValue,omitempty"`
+	IsNull       *bool       `json:"isNull,omitempty"`
+	LongValue    *int64      `json:"longValue,omitempty"`
+	StringValue  *string     `json:"stringValue,omitempty"`
+}
+
+// ArrayValue of a field, of which exactly one member is set.
+type ArrayValue struct {
+	ArrayValues   *[]ArrayValue `json:"arrayValues,omitempty"`
+	BooleanValues *[]bool       `json:"booleanValues,omitempty"`
+	DoubleValues  *[]float64    `json:"doubleValues,omitempty"`
+	LongValues    *[]int64      `json:"longValues,omitempty"`
+	StringValues  *[]string     `json:"stringValues,omitempty"`
+}
+
+// Parameter of a statement.
+type Parameter struct {
+	Name     string `json:"name"`
+	TypeHint string `json:"typeHint,omitempty"`
+	Value    Field  `json:"value"`
+}
+
+// ColumnMetadata of a result.
+type ColumnMetadata struct {
+	ArrayBaseColumnType int32  `json:"arrayBaseColumnType"`
+	IsAutoIncrement     bool   `json:"isAutoIncrement"`
+	IsCaseSensitive     bool   `json:"isCaseSensitive"`
+	IsCurrency          bool   `json:"isCurrency"`
+	IsSigned            bool   `json:"isSigned"`
+	Label               string `json:"label,omitempty"`
+	Name                string `json:"name,omitempty"`
+	Nullable            int32  `json:"nullable"`
+	Precision           int32  `json:"precision"`
+	Scale               int32  `json:"scale"`
+	SchemaName          string `json:"schemaName,omitempty"`
+	TableName           string `json:"tableName,omitempty"`
+	Type                int32  `json:"type"`
+	TypeName            string `json:"typeName,omitempty"`
+}
+
+// ToField of the SDK. Fields without a member are null.
+func (f Field) ToField() types.Field {
+	switch {
+	case f.ArrayValue != nil:
+		return &types.FieldMemberArrayValue{Value: f.ArrayValue.toArrayValue()}
+	case f.BlobValue != nil:
+		return &types.FieldMemberBlobValue{Value: *f.BlobValue}
+	case f.BooleanValue != nil:
+		return &types.FieldMemberBooleanValue{Value: *f.BooleanValue}
+	case f.DoubleValue != nil:
+		return &types.FieldMemberDoubleValue{Value: *f.DoubleValue}
+	case f.LongValue != nil:
+		return &types.FieldMemberLongValue{Value: *f.LongValue}
+	case f.StringValue != nil:
+		return &types.FieldMemberStringValue{Value: *f.StringValue}
+	}
+	return &types.FieldMemberIsNull{Value: true}
+}
+
+func (a ArrayValue) toArrayValue() types.ArrayValue {
+	switch {
+	case a.ArrayValues != nil:
+		values := make([]types.ArrayValue, len(*a.ArrayValues))
+		for i, value := range *a.ArrayValues {
+			values[i] = value.toArrayValue()
+		}
+		return &types.ArrayValueMemberArrayValues{Value: values}
+	case a.BooleanValues != nil:
+		return &types.ArrayValueMemberBooleanValues{Value: *a.BooleanValues}
+	case a.DoubleValues != nil:
+		return &types.ArrayValueMemberDoubleValues{Value: *a.DoubleValues}
+	case a.LongValues != nil:
+		return &types.ArrayValueMemberLongValues{Value: *a.LongValues}
+	case a.StringValues != nil:
+		return &types.ArrayValueMemberStringValues{Value: *a.StringValues}
+	}
+	return &types.ArrayValueMemberStringValues{Value: []string{}}
+}
+
+// FromField of the SDK.
+func FromField(f types.Field) Field {
+	switch v := f.(type) {
+	case *types.FieldMemberArrayValue:
+		array := fromArrayValue(v.Value)
+		return Field{ArrayValue: &array}
+	case *types.FieldMemberBlobValue:
+		return Field{BlobValue: &v.Value}
+	case *types.FieldMemberBooleanValue:
+		return Field{BooleanValue: &v.Value}
+	case *types.FieldMemberDoubleValue:
+		return Field{DoubleValue: &v.Value}
+	case *types.FieldMemberLongValue:
+		return Field{LongValue: &v.Value}
+	case *types.FieldMemberStringValue:
+		return Field{StringValue: &v.Value}
+	}
+	return Field{IsNull: aws.Bool(true)}
+}
+
+func fromArrayValue(a types.ArrayValue) ArrayValue {
+	switch v := a.(type) {
+	case *types.ArrayValueMemberArrayValues:
+		values := make([]ArrayValue, len(v.Value))
+		for i, value := range v.Value {
+			values[i] = fromArrayValue(value)
+		}
+		return ArrayValue{ArrayValues: &values}
+	case *types.ArrayValueMemberBooleanValues:
+		return ArrayValue{BooleanValues: &v.Value}
+	case *types.ArrayValueMemberDoubleValues:
+		return ArrayValue{DoubleValues: &v.Value}
+	case *types.ArrayValueMemberLongValues:
+		return ArrayValue{LongValues: &v.Value}
+	case *types.ArrayValueMemberStringValues:
+		return ArrayValue{StringValues: &v.Value}
+	}
+	return ArrayValue{StringValues: &[]string{}}
+}
+
+// ToFields of the SDK, preserving nil.
+func ToFields(fields []Field) []types.Field {
+	if fields == nil {
+		return nil
+	}
+	converted := make([]types.Field, len(fields))
+	for i, f := range fields {
+		converted[i] = f.ToField()
+	}
+	return converted
+}
+
+// FromFields of the SDK, preserving nil.
+func FromFields(fields []types.Field) []Field {
+	if fields == nil {
+		return nil
+	}
+	converted := make([]Field, len(fields))
+	for i, f := range fields {
+		converted[i] = FromField(f)
+	}
+	return converted
+}
+
+// ToParameters of the SDK.
+func ToParameters(params []Parameter) []types.SqlParameter {
+	converted := make([]types.SqlParameter, len(params))
+	for i, param := range params {
+		converted[i] = types.SqlParameter{
+			Name:     aws.String(param.Name),
+			TypeHint: types.TypeHint(param.TypeHint),
+			Value:    param.Value.ToField(),
+		}
+	}
+	return converted
+}
+
+// FromParameters of the SDK.
+func FromParameters(params []types.SqlParameter) []Parameter {
+	converted := make([]Parameter, len(params))
+	for i, param := range params {
+		converted[i] = Parameter{
+			Name:     aws.ToString(param.Name),
+			TypeHint: string(param.TypeHint),
+			Value:    FromField(param.Value),
+		}
+	}
+	return converted
+}
+
+// ToColumnMetadata of the SDK, preserving nil.
+func ToColumnMetadata(columns []ColumnMetadata) []types.ColumnMetadata {
+	if columns == nil {
+		return nil
+	}
+	converted := make([]types.ColumnMetadata, len(columns))
+	for i, c := range columns {
+		converted[i] = types.ColumnMetadata{
+			ArrayBaseColumnType: c.ArrayBaseColumnType,
+			IsAutoIncrement:     c.IsAutoIncrement,
+			IsCaseSensitive:     c.IsCaseSensitive,
+			IsCurrency:          c.IsCurrency,
+			IsSigned:            c.IsSigned,
+			Label:               optional(c.Label),
+			Name:                optional(c.Name),
+			Nullable:            c.Nullable,
+			Precision:           c.Precision,
+			Scale:               c.Scale,
+			SchemaName:          optional(c.SchemaName),
+			TableName:           optional(c.TableName),
+			Type:                c.Type,
+			TypeName:            optional(c.TypeName),
+		}
+	}
+	return converted
+}
+
+// FromColumnMetadata of the SDK, preserving nil.
+func FromColumnMetadata(columns []types.ColumnMetadata) []ColumnMetadata {
+	if columns == nil {
+		return nil
+	}
+	converted := make([]ColumnMetadata, len(columns))
+	for i, c := range columns {
+		converted[i] = ColumnMetadata{
+			ArrayBaseColumnType: c.ArrayBaseColumnType,
+			IsAutoIncrement:     c.IsAutoIncrement,
+			IsCaseSensitive:     c.IsCaseSensitive,
+			IsCurrency:          c.IsCurrency,
+			IsSigned:            c.IsSigned,
+			Label:               aws.ToString(c.Label),
+			Name:                aws.ToString(c.Name),
+			Nullable:            c.Nullable,
+			Precision:           c.Precision,
+			Scale:               c.Scale,
+			SchemaName:          aws.ToString(c.SchemaName),
+			TableName:           aws.ToString(c.TableName),
+			Type:                c.Type,
+			TypeName:            aws.ToString(c.TypeName),
+		}
+	}
+	return converted
+}
+
+// optional string of the SDK, which is nil when empty.
+func optional(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return aws.String(s)
+}
@@ -0,0 +1,104 @@
+package rds
+
+import (
+	"context"
+	"log/slog"
+)
+
+var _ Logger = (*SlogLogger)(nil) // explicit compile time type check
+
+// Logger receives the structured events emitted by the driver.
+type Logger interface {
+	Log(ctx context.Context, level slog.Level, msg string, attrs ...slog.Attr)
+}
+
+// NewSlogLogger adapts a slog.Logger to the Logger interface. If nil, slog.Default() is used.
+func NewSlogLogger(logger *slog.Logger) *SlogLogger {
+	return &SlogLogger{logger: logger}
+}
+
+// SlogLogger writes events to a slog.Logger, and is the default Logger.
+type SlogLogger struct {
+	logger *slog.Logger
+}
+
+// Log the event.
+func (l *SlogLogger) Log(ctx context.Context, level slog.Level, msg string, attrs ...slog.Attr) {
+	logger := l.logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	logger.LogAttrs(ctx, level, msg, attrs...)
+}
+
+// LogLevels at which each kind of event is logged.
+type LogLevels struct {
+	// Wakeup of the cluster, and detection of its dialect
+	Wakeup slog.Level
+	// Retry of a failed request to wake up the cluster
+	Retry slog.Level
+	// ConversionFailure of a field returned by the Data API
+	ConversionFailure slog.Level
+	// Transaction lifecycle events, such as begin, commit, rollback and expiry
+	Transaction slog.Level
+}
+
+// DefaultLogLevels used unless a Config sets its own.
+var DefaultLogLevels = LogLevels{
+	Wakeup:            slog.LevelDebug,
+	Retry:             slog.LevelWarn,
+	ConversionFailure: slog.LevelError,
+	Transaction:       slog.LevelDebug,
+}
+
+// eventLogger emits the driver's events to the configured Logger at the configured levels. A nil
+// eventLogger discards all events.
+type eventLogger struct {
+	logger Logger
+	levels LogLevels
+}
+
+func newEventLogger(conf *Config) *eventLogger {
+	l := &eventLogger{
+		logger: conf.Logger,
+		levels: DefaultLogLevels,
+	}
+	if l.logger == nil {
+		l.logger = NewSlogLogger(nil)
+	}
+	if conf.LogLevels != nil {
+		l.levels = *conf.LogLevels
+	}
+	return l
+}
+
+func (l *eventLogger) log(ctx context.Context, level slog.Level, msg string, attrs ...slog.Attr) {
+	if l == nil {
+		return
+	}
+	l.logger.Log(ctx, level, msg, attrs...)
+}
+
+func (l *eventLogger) wakeup(ctx context.Context, msg string, attrs ...slog.Attr) {
+	if l != nil {
+		l.log(ctx, l.levels.Wakeup, msg, attrs...)
+	}
+}
+
+func (l *eventLogger) retry(ctx context.Context, msg string, attrs ...slog.Attr) {
+	if l != nil {
+		l.log(ctx, l.levels.Retry, msg, attrs...)
+	}
+}
+
+func (l *eventLogger) conversionFailure(ctx context.Context, msg string, attrs ...slog.Attr) {
+	if l != nil {
+		l.log(ctx, l.levels.ConversionFailure, msg, attrs...)
+	}
+}
+
+func (l *eventLogger) transaction(ctx context.Context, msg string, attrs ...slog.Attr) {
+	if l != nil {
+		l.log(ctx, l.levels.Transaction, msg, attrs...)
+	}
+}
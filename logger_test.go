@@ -0,0 +1,121 @@
+package rds_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql/driver"
+	"errors"
+	"log/slog"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rdsdata"
+	"github.com/aws/aws-sdk-go-v2/service/rdsdata/types"
+	"github.com/golang/mock/gomock"
+	"github.com/krotscheck/go-rds-driver"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// loggedEvent captured by a recordingLogger
+type loggedEvent struct {
+	level slog.Level
+	msg   string
+	attrs map[string]slog.Value
+}
+
+// recordingLogger captures all logged events
+type recordingLogger struct {
+	mu     sync.Mutex
+	events []loggedEvent
+}
+
+func (l *recordingLogger) Log(_ context.Context, level slog.Level, msg string, attrs ...slog.Attr) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	event := loggedEvent{level: level, msg: msg, attrs: map[string]slog.Value{}}
+	for _, attr := range attrs {
+		event.attrs[attr.Key] = attr.Value
+	}
+	l.events = append(l.events, event)
+}
+
+func (l *recordingLogger) messages() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var messages []string
+	for _, event := range l.events {
+		messages = append(messages, event.msg)
+	}
+	return messages
+}
+
+func Test_Logger(t *testing.T) {
+	ctx := context.Background()
+
+	Convey("Logger", t, func() {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		mockRDS := NewMockAWSClientInterface(ctrl)
+		logger := &recordingLogger{}
+		conf := rds.NewConfig("resourceARN", "secretARN", "database", "region")
+		conf.Logger = logger
+
+		Convey("Slog adapter", func() {
+			var buf bytes.Buffer
+			slogger := rds.NewSlogLogger(slog.New(slog.NewTextHandler(&buf, nil)))
+			slogger.Log(ctx, slog.LevelWarn, "rds: test", slog.String("key", "value"))
+			So(buf.String(), ShouldContainSubstring, `level=WARN msg="rds: test" key=value`)
+		})
+
+		Convey("Wakeup retries", func() {
+			gomock.InOrder(
+				ExpectStatement(mockRDS, "/* wakeup */ SELECT VERSION()").Return(nil, errors.New("resuming")),
+				ExpectStatement(mockRDS, "/* wakeup */ SELECT VERSION()").Return(&rdsdata.ExecuteStatementOutput{
+					Records: [][]types.Field{{&types.FieldMemberStringValue{Value: "5.7.0"}}},
+				}, nil),
+			)
+
+			_, err := rds.NewConnector(rds.NewDriver(), mockRDS, conf).Wakeup()
+			So(err, ShouldBeNil)
+			So(logger.messages(), ShouldResemble, []string{"rds: retrying wakeup after error", "rds: cluster awake"})
+			So(logger.events[0].level, ShouldEqual, slog.LevelWarn)
+			So(logger.events[0].attrs["attempt"].Int64(), ShouldEqual, 1)
+			So(logger.events[1].level, ShouldEqual, slog.LevelDebug)
+			So(logger.events[1].attrs["version"].String(), ShouldEqual, "5.7.0")
+		})
+
+		Convey("Conversion failures", func() {
+			conf.LogLevels = &rds.LogLevels{ConversionFailure: slog.LevelWarn}
+			conn := rds.NewConnection(ctx, mockRDS, conf, rds.NewMySQL(conf)).(*rds.Connection)
+			ExpectStatement(mockRDS, "SELECT amount FROM a").Return(&rdsdata.ExecuteStatementOutput{
+				ColumnMetadata: []types.ColumnMetadata{{Label: aws.String("amount"), TypeName: aws.String("DECIMAL")}},
+				Records:        [][]types.Field{{&types.FieldMemberStringValue{Value: "not a number"}}},
+			}, nil)
+
+			rows, err := conn.QueryContext(ctx, "SELECT amount FROM a", nil)
+			So(err, ShouldBeNil)
+			So(rows.Next(make([]driver.Value, 1)), ShouldNotBeNil)
+
+			So(logger.messages(), ShouldResemble, []string{"rds: failed to convert field"})
+			So(logger.events[0].level, ShouldEqual, slog.LevelWarn)
+			So(logger.events[0].attrs["label"].String(), ShouldEqual, "amount")
+			So(logger.events[0].attrs["type_name"].String(), ShouldEqual, "DECIMAL")
+		})
+
+		Convey("Transaction lifecycle", func() {
+			conn := rds.NewConnection(ctx, mockRDS, conf, rds.NewMySQL(conf)).(*rds.Connection)
+			ExpectBeginTransaction(mockRDS, "transactionID")
+			mockRDS.EXPECT().CommitTransaction(gomock.Any(), gomock.Any()).
+				Return(&rdsdata.CommitTransactionOutput{TransactionStatus: aws.String("Transaction Committed")}, nil)
+
+			tx, err := conn.BeginTx(ctx, driver.TxOptions{})
+			So(err, ShouldBeNil)
+			So(tx.Commit(), ShouldBeNil)
+
+			So(logger.messages(), ShouldResemble, []string{"rds: transaction begun", "rds: transaction committed"})
+			So(logger.events[1].attrs["transaction_id"].String(), ShouldEqual, "transactionID")
+			So(logger.events[1].attrs["status"].String(), ShouldEqual, "Transaction Committed")
+		})
+	})
+}
@@ -0,0 +1,264 @@
+package rds
+
+import (
+	"expvar"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/rdsdata"
+	"github.com/aws/aws-sdk-go-v2/service/rdsdata/types"
+)
+
+var _ Metrics = (*ExpvarMetrics)(nil) // explicit compile time type check
+var _ Metrics = NoopMetrics{}          // explicit compile time type check
+
+// Data API calls, as reported to Metrics.
+const (
+	CallExecuteStatement    = "ExecuteStatement"
+	CallBeginTransaction    = "BeginTransaction"
+	CallCommitTransaction   = "CommitTransaction"
+	CallRollbackTransaction = "RollbackTransaction"
+)
+
+// TransactionOutcome reported to Metrics once a transaction completes.
+type TransactionOutcome string
+
+const (
+	// TransactionCommitted once the Data API confirmed the commit
+	TransactionCommitted TransactionOutcome = "committed"
+	// TransactionRolledBack once the Data API confirmed the rollback
+	TransactionRolledBack TransactionOutcome = "rolled_back"
+	// TransactionExpired once the Data API, or the driver, found the transaction terminated
+	TransactionExpired TransactionOutcome = "expired"
+)
+
+// Metrics collects the driver's usage of the Data API, which is billed per request and payload size.
+type Metrics interface {
+	// ObserveCall to the Data API, with its latency and error, if any.
+	ObserveCall(call string, duration time.Duration, err error)
+	// ObserveResult of a statement, with the number of records and the approximate size of the
+	// values it returned, in bytes.
+	ObserveResult(records int, size int)
+	// ObserveRetry of a failed request to wake up the cluster.
+	ObserveRetry(err error)
+	// ObserveWakeup of the cluster, with the time and attempts it took.
+	ObserveWakeup(duration time.Duration, attempts int, err error)
+	// ObserveTransaction once it completes.
+	ObserveTransaction(outcome TransactionOutcome)
+}
+
+var defaultMetrics struct {
+	once    sync.Once
+	metrics *ExpvarMetrics
+}
+
+// DefaultMetrics published via expvar as "rds", and used unless a Config sets its own.
+func DefaultMetrics() *ExpvarMetrics {
+	defaultMetrics.once.Do(func() {
+		defaultMetrics.metrics = NewExpvarMetrics()
+		expvar.Publish("rds", defaultMetrics.metrics.Map)
+	})
+	return defaultMetrics.metrics
+}
+
+// latencyBuckets in milliseconds, and sizeBuckets in bytes, of the expvar histograms.
+var (
+	latencyBuckets = []float64{5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000, 30000}
+	recordBuckets  = []float64{0, 1, 10, 100, 1000, 10000}
+	sizeBuckets    = []float64{1024, 4096, 32768, 131072, 1048576}
+)
+
+// NewExpvarMetrics which aren't published. Publish its Map with expvar.Publish.
+func NewExpvarMetrics() *ExpvarMetrics {
+	m := &ExpvarMetrics{
+		Map:            new(expvar.Map),
+		calls:          new(expvar.Map),
+		errors:         new(expvar.Map),
+		latency:        new(expvar.Map),
+		records:        newExpvarHistogram(recordBuckets),
+		size:           newExpvarHistogram(sizeBuckets),
+		retries:        new(expvar.Int),
+		wakeups:        new(expvar.Map),
+		wakeupDuration: newExpvarHistogram(latencyBuckets),
+		transactions:   new(expvar.Map),
+	}
+	m.Set("calls", m.calls)
+	m.Set("errors", m.errors)
+	m.Set("latency_ms", m.latency)
+	m.Set("records", m.records)
+	m.Set("response_bytes", m.size)
+	m.Set("retries", m.retries)
+	m.Set("wakeups", m.wakeups)
+	m.Set("wakeup_duration_ms", m.wakeupDuration)
+	m.Set("transactions", m.transactions)
+	return m
+}
+
+// ExpvarMetrics collects Metrics into an expvar.Map, with histograms reported as cumulative
+// bucket counts, their count and their sum.
+type ExpvarMetrics struct {
+	*expvar.Map
+
+	mu             sync.Mutex
+	calls          *expvar.Map
+	errors         *expvar.Map
+	latency        *expvar.Map
+	records        *expvar.Map
+	size           *expvar.Map
+	retries        *expvar.Int
+	wakeups        *expvar.Map
+	wakeupDuration *expvar.Map
+	transactions   *expvar.Map
+}
+
+// ObserveCall to the Data API.
+func (m *ExpvarMetrics) ObserveCall(call string, duration time.Duration, err error) {
+	m.calls.Add(call, 1)
+	if err != nil {
+		m.errors.Add(call, 1)
+	}
+
+	m.mu.Lock()
+	histogram, ok := m.latency.Get(call).(*expvar.Map)
+	if !ok {
+		histogram = newExpvarHistogram(latencyBuckets)
+		m.latency.Set(call, histogram)
+	}
+	m.mu.Unlock()
+	observeExpvarHistogram(histogram, latencyBuckets, milliseconds(duration))
+}
+
+// ObserveResult of a statement.
+func (m *ExpvarMetrics) ObserveResult(records int, size int) {
+	observeExpvarHistogram(m.records, recordBuckets, float64(records))
+	observeExpvarHistogram(m.size, sizeBuckets, float64(size))
+}
+
+// ObserveRetry of a failed request to wake up the cluster.
+func (m *ExpvarMetrics) ObserveRetry(error) {
+	m.retries.Add(1)
+}
+
+// ObserveWakeup of the cluster.
+func (m *ExpvarMetrics) ObserveWakeup(duration time.Duration, _ int, err error) {
+	if err != nil {
+		m.wakeups.Add("failed", 1)
+	} else {
+		m.wakeups.Add("succeeded", 1)
+	}
+	observeExpvarHistogram(m.wakeupDuration, latencyBuckets, milliseconds(duration))
+}
+
+// ObserveTransaction once it completes.
+func (m *ExpvarMetrics) ObserveTransaction(outcome TransactionOutcome) {
+	m.transactions.Add(string(outcome), 1)
+}
+
+func newExpvarHistogram(buckets []float64) *expvar.Map {
+	histogram := new(expvar.Map)
+	for _, bucket := range buckets {
+		histogram.Set("le_"+strconv.FormatFloat(bucket, 'f', -1, 64), new(expvar.Int))
+	}
+	histogram.Set("count", new(expvar.Int))
+	histogram.Set("sum", new(expvar.Float))
+	return histogram
+}
+
+func observeExpvarHistogram(histogram *expvar.Map, buckets []float64, value float64) {
+	for _, bucket := range buckets {
+		if value <= bucket {
+			histogram.Add("le_"+strconv.FormatFloat(bucket, 'f', -1, 64), 1)
+		}
+	}
+	histogram.Add("count", 1)
+	histogram.AddFloat("sum", value)
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// resultSize approximates the size of the values returned by a statement, as the Data API doesn't
+// report the size of its responses.
+func resultSize(output *rdsdata.ExecuteStatementOutput) int {
+	size := 0
+	for _, record := range output.Records {
+		for _, field := range record {
+			size += fieldSize(field)
+		}
+	}
+	for _, field := range output.GeneratedFields {
+		size += fieldSize(field)
+	}
+	if output.FormattedRecords != nil {
+		size += len(*output.FormattedRecords)
+	}
+	return size
+}
+
+func fieldSize(field types.Field) int {
+	switch v := field.(type) {
+	case *types.FieldMemberStringValue:
+		return len(v.Value)
+	case *types.FieldMemberBlobValue:
+		return len(v.Value)
+	case *types.FieldMemberLongValue, *types.FieldMemberDoubleValue:
+		return 8
+	case *types.FieldMemberBooleanValue, *types.FieldMemberIsNull:
+		return 1
+	case *types.FieldMemberArrayValue:
+		return arraySize(v.Value)
+	}
+	return 0
+}
+
+func arraySize(array types.ArrayValue) int {
+	switch v := array.(type) {
+	case *types.ArrayValueMemberStringValues:
+		size := 0
+		for _, s := range v.Value {
+			size += len(s)
+		}
+		return size
+	case *types.ArrayValueMemberLongValues:
+		return 8 * len(v.Value)
+	case *types.ArrayValueMemberDoubleValues:
+		return 8 * len(v.Value)
+	case *types.ArrayValueMemberBooleanValues:
+		return len(v.Value)
+	case *types.ArrayValueMemberArrayValues:
+		size := 0
+		for _, nested := range v.Value {
+			size += arraySize(nested)
+		}
+		return size
+	}
+	return 0
+}
+
+// NoopMetrics discards all metrics, and may be set on a Config to disable the DefaultMetrics.
+type NoopMetrics struct{}
+
+// ObserveCall does nothing.
+func (NoopMetrics) ObserveCall(string, time.Duration, error) {}
+
+// ObserveResult does nothing.
+func (NoopMetrics) ObserveResult(int, int) {}
+
+// ObserveRetry does nothing.
+func (NoopMetrics) ObserveRetry(error) {}
+
+// ObserveWakeup does nothing.
+func (NoopMetrics) ObserveWakeup(time.Duration, int, error) {}
+
+// ObserveTransaction does nothing.
+func (NoopMetrics) ObserveTransaction(TransactionOutcome) {}
+
+// metricsOrDefault returns the configured metrics, or the DefaultMetrics if none are.
+func metricsOrDefault(metrics Metrics) Metrics {
+	if metrics == nil {
+		return DefaultMetrics()
+	}
+	return metrics
+}
@@ -0,0 +1,157 @@
+package rds_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rdsdata"
+	"github.com/aws/aws-sdk-go-v2/service/rdsdata/types"
+	"github.com/golang/mock/gomock"
+	"github.com/krotscheck/go-rds-driver"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// recordingMetrics captures the name of each observation
+type recordingMetrics struct {
+	mu           sync.Mutex
+	calls        []string
+	results      [][2]int
+	retries      int
+	wakeups      []int
+	transactions []rds.TransactionOutcome
+}
+
+func (m *recordingMetrics) ObserveCall(call string, _ time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err != nil {
+		call += ":error"
+	}
+	m.calls = append(m.calls, call)
+}
+
+func (m *recordingMetrics) ObserveResult(records int, size int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.results = append(m.results, [2]int{records, size})
+}
+
+func (m *recordingMetrics) ObserveRetry(error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.retries++
+}
+
+func (m *recordingMetrics) ObserveWakeup(_ time.Duration, attempts int, _ error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.wakeups = append(m.wakeups, attempts)
+}
+
+func (m *recordingMetrics) ObserveTransaction(outcome rds.TransactionOutcome) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.transactions = append(m.transactions, outcome)
+}
+
+func Test_Metrics(t *testing.T) {
+	ctx := context.Background()
+
+	Convey("Metrics", t, func() {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		mockRDS := NewMockAWSClientInterface(ctrl)
+		metrics := &recordingMetrics{}
+		conf := rds.NewConfig("resourceARN", "secretARN", "database", "region")
+		conf.Metrics = metrics
+
+		Convey("Wakeup", func() {
+			gomock.InOrder(
+				ExpectStatement(mockRDS, "/* wakeup */ SELECT VERSION()").Return(nil, errors.New("resuming")),
+				ExpectStatement(mockRDS, "/* wakeup */ SELECT VERSION()").Return(&rdsdata.ExecuteStatementOutput{
+					Records: [][]types.Field{{&types.FieldMemberStringValue{Value: "5.7.0"}}},
+				}, nil),
+			)
+
+			connector := rds.NewConnector(rds.NewDriver(), mockRDS, conf)
+			So(connector.Metrics(), ShouldEqual, metrics)
+			_, err := connector.Wakeup()
+			So(err, ShouldBeNil)
+			So(metrics.calls, ShouldResemble, []string{"ExecuteStatement:error", "ExecuteStatement"})
+			So(metrics.retries, ShouldEqual, 1)
+			So(metrics.wakeups, ShouldResemble, []int{2})
+		})
+
+		Convey("Statements and transactions", func() {
+			conn := rds.NewConnection(ctx, mockRDS, conf, rds.NewMySQL(conf)).(*rds.Connection)
+			ExpectBeginTransaction(mockRDS, "transactionID").Times(2)
+			ExpectStatement(mockRDS, "SELECT name FROM a").Return(&rdsdata.ExecuteStatementOutput{
+				Records: [][]types.Field{
+					{&types.FieldMemberStringValue{Value: "four"}},
+					{&types.FieldMemberLongValue{Value: 1}},
+				},
+			}, nil)
+			mockRDS.EXPECT().CommitTransaction(gomock.Any(), gomock.Any()).Return(&rdsdata.CommitTransactionOutput{}, nil)
+			ExpectStatement(mockRDS, "SELECT 1").Return(nil, &types.TransactionNotFoundException{Message: aws.String("Transaction transactionID is not found")})
+
+			tx, err := conn.BeginTx(ctx, driver.TxOptions{})
+			So(err, ShouldBeNil)
+			_, err = conn.QueryContext(ctx, "SELECT name FROM a", nil)
+			So(err, ShouldBeNil)
+			So(tx.Commit(), ShouldBeNil)
+
+			_, err = conn.BeginTx(ctx, driver.TxOptions{})
+			So(err, ShouldBeNil)
+			_, err = conn.ExecContext(ctx, "SELECT 1", nil)
+			So(errors.Is(err, rds.ErrTransactionExpired), ShouldBeTrue)
+
+			So(metrics.calls, ShouldResemble, []string{
+				"BeginTransaction",
+				"ExecuteStatement",
+				"CommitTransaction",
+				"BeginTransaction",
+				"ExecuteStatement:error",
+			})
+			So(metrics.results, ShouldResemble, [][2]int{{2, 12}})
+			So(metrics.transactions, ShouldResemble, []rds.TransactionOutcome{rds.TransactionCommitted, rds.TransactionExpired})
+		})
+	})
+
+	Convey("Expvar", t, func() {
+		metrics := rds.NewExpvarMetrics()
+		metrics.ObserveCall(rds.CallExecuteStatement, 20*time.Millisecond, nil)
+		metrics.ObserveCall(rds.CallExecuteStatement, 2*time.Second, errors.New("failed"))
+		metrics.ObserveResult(5, 2048)
+		metrics.ObserveTransaction(rds.TransactionCommitted)
+
+		var raw map[string]json.RawMessage
+		So(json.Unmarshal([]byte(metrics.String()), &raw), ShouldBeNil)
+		values := map[string]map[string]interface{}{}
+		for _, key := range []string{"calls", "errors", "latency_ms", "records", "response_bytes", "transactions"} {
+			value := map[string]interface{}{}
+			So(json.Unmarshal(raw[key], &value), ShouldBeNil)
+			values[key] = value
+		}
+		So(string(raw["retries"]), ShouldEqual, "0")
+		So(values["calls"]["ExecuteStatement"], ShouldEqual, 2)
+		So(values["errors"]["ExecuteStatement"], ShouldEqual, 1)
+		So(values["latency_ms"]["ExecuteStatement"], ShouldContainKey, "le_25")
+		latency := values["latency_ms"]["ExecuteStatement"].(map[string]interface{})
+		So(latency["le_25"], ShouldEqual, 1)
+		So(latency["le_5000"], ShouldEqual, 2)
+		So(latency["count"], ShouldEqual, 2)
+		So(values["records"]["le_10"], ShouldEqual, 1)
+		So(values["response_bytes"]["le_4096"], ShouldEqual, 1)
+		So(values["transactions"]["committed"], ShouldEqual, 1)
+
+		So(rds.DefaultMetrics(), ShouldEqual, rds.DefaultMetrics())
+		So(expvar.Get("rds"), ShouldEqual, rds.DefaultMetrics().Map)
+	})
+}
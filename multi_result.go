@@ -0,0 +1,164 @@
+package rds
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+
+	"github.com/aws/aws-sdk-go-v2/service/rdsdata"
+)
+
+var _ driver.Result = (*MultiResult)(nil) // explicit compile time type check
+
+// StatementResult of a single statement executed as part of a MultiResult.
+type StatementResult struct {
+	// Query of the executed statement
+	Query string
+	// RowsAffected by the statement
+	RowsAffected int64
+	// GeneratedFields returned by the statement, decoded by the dialect
+	GeneratedFields []interface{}
+	// Columns of the records returned by a RETURNING clause, if capture_returning is enabled
+	Columns []string
+	// Records returned by a RETURNING clause, if capture_returning is enabled
+	Records [][]interface{}
+}
+
+// MultiResult of executing one or more statements, such as when split_multi is enabled. It reports
+// the totals as any other Result, as well as a breakdown of each statement.
+type MultiResult struct {
+	*Result
+	Statements []StatementResult
+
+	dialect Dialect
+	results []*rdsdata.ExecuteStatementOutput
+}
+
+// NewMultiResult for the executed queries, decoding generated fields by their value type.
+func NewMultiResult(dialect Dialect, queries []string, results []*rdsdata.ExecuteStatementOutput) (*MultiResult, error) {
+	statements := make([]StatementResult, len(results))
+	for i, r := range results {
+		statements[i].RowsAffected = r.NumberOfRecordsUpdated
+		if i < len(queries) {
+			statements[i].Query = queries[i]
+		}
+		// Generated fields aren't columns, so neither the dialect's nor any registered converters apply.
+		converter := ConvertDefaults()
+		for _, field := range r.GeneratedFields {
+			value, err := converter(field)
+			if err != nil {
+				return nil, err
+			}
+			statements[i].GeneratedFields = append(statements[i].GeneratedFields, value)
+		}
+	}
+	result := newResult(results)
+	if !supportsLastInsertID(dialect) {
+		result.lastInsertIDErr = ErrLastInsertIDNotSupported
+	}
+	return &MultiResult{
+		Result:     result,
+		Statements: statements,
+		dialect:    dialect,
+		results:    results,
+	}, nil
+}
+
+// Rows returned by the executed statements, such as by a RETURNING clause, as they would have been
+// by a query. Statements which returned no columns aren't included as result sets.
+func (r *MultiResult) Rows() *Rows {
+	return newQueryRows(r.dialect, r.results, false)
+}
+
+// Returning returns the columns and records returned by the RETURNING clause of the last statement
+// which had one. Requires capture_returning to be enabled.
+func (r *MultiResult) Returning() (columns []string, records [][]interface{}) {
+	for i := len(r.Statements) - 1; i >= 0; i-- {
+		if r.Statements[i].Columns != nil {
+			return r.Statements[i].Columns, r.Statements[i].Records
+		}
+	}
+	return nil, nil
+}
+
+// captureReturning decodes the records returned by each statement. Should the last of these be an
+// integer in its first column, as with RETURNING id, it's also reported as the LastInsertId.
+func (r *MultiResult) captureReturning(dialect Dialect, results []*rdsdata.ExecuteStatementOutput) error {
+	for i, output := range results {
+		if len(output.ColumnMetadata) == 0 {
+			continue
+		}
+		rows := NewRows(dialect, []*rdsdata.ExecuteStatementOutput{output})
+		r.Statements[i].Columns = rows.Columns()
+		r.Statements[i].Records = [][]interface{}{}
+		for {
+			dest := make([]driver.Value, len(r.Statements[i].Columns))
+			if err := rows.Next(dest); err == io.EOF {
+				break
+			} else if err != nil {
+				return err
+			}
+			record := make([]interface{}, len(dest))
+			for j, value := range dest {
+				record[j] = value
+			}
+			r.Statements[i].Records = append(r.Statements[i].Records, record)
+		}
+	}
+
+	_, records := r.Returning()
+	if len(records) > 0 && len(records[len(records)-1]) > 0 {
+		if id, ok := records[len(records)-1][0].(int64); ok {
+			r.lastInsertID = id
+			r.lastInsertIDErr = nil
+		}
+	}
+	return nil
+}
+
+// ExecMulti executes the query on the passed connection, returning a breakdown of each statement
+// it was split into.
+func ExecMulti(ctx context.Context, conn *sql.Conn, query string, args ...interface{}) (result *MultiResult, err error) {
+	err = conn.Raw(func(driverConn interface{}) error {
+		rdsConn, ok := driverConn.(*Connection)
+		if !ok {
+			return fmt.Errorf("multi results require an %s connection", DRIVERNAME)
+		}
+		namedValues, err := rdsConn.namedValues(args)
+		if err != nil {
+			return err
+		}
+		stmt, err := rdsConn.PrepareContext(ctx, query)
+		if err != nil {
+			return err
+		}
+		result, err = stmt.(*Statement).exec(ctx, namedValues)
+		return err
+	})
+	return
+}
+
+// namedValues converts arguments as database/sql would before passing them to the driver. Named
+// arguments aren't given an ordinal, as the dialects don't accept a mix of both.
+func (r *Connection) namedValues(args []interface{}) ([]driver.NamedValue, error) {
+	namedValues := make([]driver.NamedValue, len(args))
+	for i, arg := range args {
+		nv := driver.NamedValue{Ordinal: i + 1, Value: arg}
+		if named, ok := arg.(sql.NamedArg); ok {
+			nv.Name = named.Name
+			nv.Ordinal = 0
+			nv.Value = named.Value
+		}
+		if err := r.CheckNamedValue(&nv); err == driver.ErrSkip {
+			value, err := driver.DefaultParameterConverter.ConvertValue(nv.Value)
+			if err != nil {
+				return nil, fmt.Errorf("converting argument %d: %w", i+1, err)
+			}
+			nv.Value = value
+		}
+		namedValues[i] = nv
+	}
+	return namedValues, nil
+}
@@ -0,0 +1,162 @@
+package rds_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rdsdata"
+	"github.com/aws/aws-sdk-go-v2/service/rdsdata/types"
+	"github.com/golang/mock/gomock"
+	"github.com/krotscheck/go-rds-driver"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func Test_MultiResult(t *testing.T) {
+	ctx := context.Background()
+
+	Convey("NewMultiResult", t, func() {
+		result, err := rds.NewMultiResult(rds.NewPostgres(TestPostgresConfig), []string{
+			"INSERT INTO a (name) VALUES ('one') RETURNING id, name",
+			"UPDATE b SET name = 'two'",
+		}, []*rdsdata.ExecuteStatementOutput{
+			{
+				NumberOfRecordsUpdated: 1,
+				GeneratedFields: []types.Field{
+					&types.FieldMemberLongValue{Value: 10},
+					&types.FieldMemberStringValue{Value: "one"},
+				},
+			},
+			{NumberOfRecordsUpdated: 3},
+		})
+		So(err, ShouldBeNil)
+
+		rowsAffected, err := result.RowsAffected()
+		So(err, ShouldBeNil)
+		So(rowsAffected, ShouldEqual, 4)
+
+		_, err = result.LastInsertId()
+		So(err, ShouldEqual, rds.ErrLastInsertIDNotSupported)
+
+		So(result.Statements, ShouldResemble, []rds.StatementResult{
+			{
+				Query:           "INSERT INTO a (name) VALUES ('one') RETURNING id, name",
+				RowsAffected:    1,
+				GeneratedFields: []interface{}{int64(10), "one"},
+			},
+			{
+				Query:        "UPDATE b SET name = 'two'",
+				RowsAffected: 3,
+			},
+		})
+	})
+
+	Convey("Generated fields aren't matched against registered converters", t, func() {
+		conf := rds.NewConfig("resourceARN", "secretARN", "database", "region")
+		conf.Converters = rds.NewConverterRegistry()
+		conf.Converters.RegisterFieldConverterFunc(func(column types.ColumnMetadata) bool {
+			return *column.TypeName == "INT"
+		}, func(field types.Field) (interface{}, error) {
+			return "registered", nil
+		})
+
+		result, err := rds.NewMultiResult(rds.NewMySQL(conf), []string{"INSERT INTO a (name) VALUES ('one')"},
+			[]*rdsdata.ExecuteStatementOutput{{
+				NumberOfRecordsUpdated: 1,
+				GeneratedFields:        []types.Field{&types.FieldMemberLongValue{Value: 10}},
+			}})
+		So(err, ShouldBeNil)
+		So(result.Statements[0].GeneratedFields, ShouldResemble, []interface{}{int64(10)})
+	})
+
+	Convey("ExecMulti", t, func() {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		mockRDS := NewMockAWSClientInterface(ctrl)
+		conf := rds.NewConfig("resourceARN", "secretARN", "database", "region")
+		conf.SplitMulti = true
+		ExpectWakeup(mockRDS, conf)
+
+		db := sql.OpenDB(rds.NewConnector(rds.NewDriver(), mockRDS, conf))
+		defer db.Close()
+		conn, err := db.Conn(ctx)
+		So(err, ShouldBeNil)
+		defer conn.Close()
+
+		ExpectStatement(mockRDS, "INSERT INTO a VALUES (:id)").Return(&rdsdata.ExecuteStatementOutput{
+			NumberOfRecordsUpdated: 1,
+			GeneratedFields:        []types.Field{&types.FieldMemberLongValue{Value: 1}},
+		}, nil)
+		ExpectStatement(mockRDS, "INSERT INTO b VALUES (:id)").Return(&rdsdata.ExecuteStatementOutput{
+			NumberOfRecordsUpdated: 2,
+		}, nil)
+
+		result, err := rds.ExecMulti(ctx, conn, "INSERT INTO a VALUES (:id); INSERT INTO b VALUES (:id)", sql.Named("id", 1))
+		So(err, ShouldBeNil)
+		So(len(result.Statements), ShouldEqual, 2)
+		So(result.Statements[0].Query, ShouldEqual, "INSERT INTO a VALUES (:id)")
+		So(result.Statements[0].GeneratedFields, ShouldResemble, []interface{}{int64(1)})
+		So(result.Statements[1].RowsAffected, ShouldEqual, 2)
+	})
+
+	Convey("Capture Returning", t, func() {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		mockRDS := NewMockAWSClientInterface(ctrl)
+		conf := rds.NewConfig("resourceARN", "secretARN", "database", "region")
+		conf.CaptureReturning = true
+		conn := rds.NewConnection(ctx, mockRDS, conf, rds.NewPostgres(conf)).(*rds.Connection)
+
+		Convey("Records RETURNING records and the last inserted id", func() {
+			ExpectStatement(mockRDS, "INSERT INTO a (name) VALUES ('one'), ('two') RETURNING id, name").Return(&rdsdata.ExecuteStatementOutput{
+				NumberOfRecordsUpdated: 2,
+				ColumnMetadata: []types.ColumnMetadata{
+					{Label: aws.String("id"), TypeName: aws.String("int8")},
+					{Label: aws.String("name"), TypeName: aws.String("text")},
+				},
+				Records: [][]types.Field{
+					{&types.FieldMemberLongValue{Value: 1}, &types.FieldMemberStringValue{Value: "one"}},
+					{&types.FieldMemberLongValue{Value: 2}, &types.FieldMemberStringValue{Value: "two"}},
+				},
+			}, nil)
+
+			result, err := conn.ExecContext(ctx, "INSERT INTO a (name) VALUES ('one'), ('two') RETURNING id, name", nil)
+			So(err, ShouldBeNil)
+			lastInsertID, err := result.LastInsertId()
+			So(err, ShouldBeNil)
+			So(lastInsertID, ShouldEqual, 2)
+
+			columns, records := result.(*rds.MultiResult).Returning()
+			So(columns, ShouldResemble, []string{"id", "name"})
+			So(records, ShouldResemble, [][]interface{}{{int64(1), "one"}, {int64(2), "two"}})
+
+			rows := result.(*rds.MultiResult).Rows()
+			So(rows.Columns(), ShouldResemble, []string{"id", "name"})
+			dest := make([]driver.Value, 2)
+			So(rows.Next(dest), ShouldBeNil)
+			So(dest, ShouldResemble, []driver.Value{int64(1), "one"})
+		})
+
+		Convey("Reports non-integer keys through Returning only", func() {
+			ExpectStatement(mockRDS, "INSERT INTO a DEFAULT VALUES RETURNING uuid").Return(&rdsdata.ExecuteStatementOutput{
+				NumberOfRecordsUpdated: 1,
+				ColumnMetadata: []types.ColumnMetadata{
+					{Label: aws.String("uuid"), TypeName: aws.String("uuid")},
+				},
+				Records: [][]types.Field{
+					{&types.FieldMemberStringValue{Value: "b3f1c2d4-1111-2222-3333-444455556666"}},
+				},
+			}, nil)
+
+			result, err := conn.ExecContext(ctx, "INSERT INTO a DEFAULT VALUES RETURNING uuid", nil)
+			So(err, ShouldBeNil)
+			_, err = result.LastInsertId()
+			So(err, ShouldEqual, rds.ErrLastInsertIDNotSupported)
+
+			_, records := result.(*rds.MultiResult).Returning()
+			So(records, ShouldResemble, [][]interface{}{{"b3f1c2d4-1111-2222-3333-444455556666"}})
+		})
+	})
+}
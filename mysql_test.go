@@ -8,6 +8,7 @@ import (
 	"time"
 
 	_ "github.com/go-sql-driver/mysql"
+	"github.com/krotscheck/go-rds-driver"
 	. "github.com/smartystreets/goconvey/convey"
 )
 
@@ -64,6 +65,35 @@ const MySQLCreateTableQuery = "CREATE TABLE IF NOT EXISTS `all_types` (" +
 
 const MySQLDropTableQuery = "DROP TABLE IF EXISTS `all_types`;"
 
+const MySQLInsertQuery = "INSERT INTO `all_types` SET" +
+	"`sql_tiny_int` = ?,`sql_small_int` = ?,`sql_medium_int` = ?,`sql_int` = ?,`sql_big_int` = ?," +
+	"`sql_decimal` = ?,`sql_float` = ?,`sql_double` = ?," +
+	// "`sql_bit` = ?," +
+	"`sql_boolean` = ?," +
+	"`sql_char` = ?,`sql_varchar` = ?," +
+	"`sql_binary` = ?,`sql_varbinary` = ?," +
+	"`sql_tinyblob` = ?,`sql_blob` = ?,`sql_mediumblob` = ?,`sql_longblob` = ?," +
+	"`sql_tinytext` = ?,`sql_text` = ?,`sql_mediumtext` = ?," +
+	"`sql_enum` = ?," +
+	"`sql_set` = ?," +
+	"`sql_date` = ?,`sql_time` = ?,`sql_datetime` = ?,`sql_timestamp` = ?,`sql_year` = ?," +
+
+	// types as automapped by gorm
+	"`string` = ?," +
+	"`bytes` = ?," +
+	"`byte` = ?," +
+	"`int8` = ?," +
+	"`int16` = ?," +
+	"`int32` = ?," +
+	"`int64` = ?," +
+	"`uint` = ?," +
+	"`uint8` = ?," +
+	"`uint16` = ?," +
+	"`uint32` = ?," +
+	"`uint64` = ?," +
+	"`float32` = ?," +
+	"`float64` = ?"
+
 // TestMySQLRow of data persisted to mysql
 type TestMySQLRow struct {
 	ID        int32
@@ -242,41 +272,23 @@ func (r *TestMySQLRow) Insert(db *sql.DB) (sql.Result, error) {
 		r.Float32,
 		r.Float64,
 	}
-	query := "INSERT INTO `all_types` SET" +
-		"`sql_tiny_int` = ?,`sql_small_int` = ?,`sql_medium_int` = ?,`sql_int` = ?,`sql_big_int` = ?," +
-		"`sql_decimal` = ?,`sql_float` = ?,`sql_double` = ?," +
-		// "`sql_bit` = ?," +
-		"`sql_boolean` = ?," +
-		"`sql_char` = ?,`sql_varchar` = ?," +
-		"`sql_binary` = ?,`sql_varbinary` = ?," +
-		"`sql_tinyblob` = ?,`sql_blob` = ?,`sql_mediumblob` = ?,`sql_longblob` = ?," +
-		"`sql_tinytext` = ?,`sql_text` = ?,`sql_mediumtext` = ?," +
-		"`sql_enum` = ?," +
-		"`sql_set` = ?," +
-		"`sql_date` = ?,`sql_time` = ?,`sql_datetime` = ?,`sql_timestamp` = ?,`sql_year` = ?," +
-
-		// types as automapped by gorm
-		"`string` = ?," +
-		"`bytes` = ?," +
-		"`byte` = ?," +
-		"`int8` = ?," +
-		"`int16` = ?," +
-		"`int32` = ?," +
-		"`int64` = ?," +
-		"`uint` = ?," +
-		"`uint8` = ?," +
-		"`uint16` = ?," +
-		"`uint32` = ?," +
-		"`uint64` = ?," +
-		"`float32` = ?," +
-		"`float64` = ?"
-	return db.Exec(query, params...)
+	return db.Exec(MySQLInsertQuery, params...)
 }
 
 // Full suite of mysql tests, starting with research queries about which data types are supportyed
 func Test_Mysql(t *testing.T) {
+	testMySQLParity(t, TestMysqlConfig, "3306")
+}
+
+// Parity tests of the Aurora MySQL 3 cluster, using the mysql8 dialect, against the local MySQL 8.0 instance.
+func Test_Mysql8(t *testing.T) {
+	testMySQLParity(t, TestMysql8Config, "3307")
+}
+
+// testMySQLParity compares the results of the RDS cluster with those of the local instance on the given port.
+func testMySQLParity(t *testing.T, conf *rds.Config, localPort string) {
 	// Create the RDS DB Instance
-	dsn := TestMysqlConfig.ToDSN()
+	dsn := conf.ToDSN()
 	rdsDB, err := sql.Open("rds", dsn)
 	if err != nil {
 		panic(err)
@@ -289,7 +301,7 @@ func Test_Mysql(t *testing.T) {
 		}
 	}()
 
-	dsn = fmt.Sprintf("%s:%s@tcp(%s:%s)/%s", "root", "supersecret", "127.0.0.1", "3306", TestMysqlConfig.Database)
+	dsn = fmt.Sprintf("%s:%s@tcp(%s:%s)/%s", "root", "supersecret", "127.0.0.1", localPort, conf.Database)
 	// Create the Local DB Instance
 	localDB, err := sql.Open("mysql", dsn)
 	if err != nil {
@@ -36,6 +36,14 @@ const PostGreSQLCreateTableQuery = "CREATE TABLE IF NOT EXISTS all_types (" +
 
 const PostgreSQLDropTableQuery = "DROP TABLE all_types;"
 
+const PostgreSQLInsertQuery = "INSERT INTO all_types (" +
+	"sql_boolean," +
+	"sql_char,sql_varchar,sql_text," +
+	"sql_small_int,sql_medium_int,sql_int," +
+	"sql_decimal,sql_numeric,sql_real,sql_byte," +
+	"sql_date,sql_time,sql_timestamp,sql_timestamptz,sql_uuid) " +
+	"VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12::date,$13::time,$14::timestamp,$15::timestamptz,$16::uuid)"
+
 // TestPostgreSQLRow of data persisted to postgres
 type TestPostgreSQLRow struct {
 	ID        int32
@@ -127,14 +135,7 @@ func (r *TestPostgreSQLRow) Insert(db *sql.DB) (sql.Result, error) {
 		r.Timestamptz, // Timestamptz string
 		r.UUID,        // UUID        string
 	}
-	query := "INSERT INTO all_types (" +
-		"sql_boolean," +
-		"sql_char,sql_varchar,sql_text," +
-		"sql_small_int,sql_medium_int,sql_int," +
-		"sql_decimal,sql_numeric,sql_real,sql_byte," +
-		"sql_date,sql_time,sql_timestamp,sql_timestamptz,sql_uuid) " +
-		"VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12::date,$13::time,$14::timestamp,$15::timestamptz,$16::uuid)"
-	return db.Exec(query, params...)
+	return db.Exec(PostgreSQLInsertQuery, params...)
 }
 
 // Full suite of postgres tests, starting with research queries about which data types are supportyed
@@ -0,0 +1,175 @@
+package rds
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql/driver"
+	"encoding/hex"
+	"log/slog"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rdsdata"
+)
+
+// Redacted replaces the value of redacted parameters in the query log.
+const Redacted = "[REDACTED]"
+
+// DefaultRedactedNames matches the names of parameters which are redacted by default.
+var DefaultRedactedNames = regexp.MustCompile(`(?i)pass|secret|token|key|credential|auth`)
+
+// RedactionRule decides whether the value of a parameter is redacted from the query log.
+type RedactionRule func(arg driver.NamedValue) bool
+
+// RedactName redacts parameters whose name matches the pattern.
+func RedactName(pattern *regexp.Regexp) RedactionRule {
+	return func(arg driver.NamedValue) bool {
+		return arg.Name != "" && pattern.MatchString(arg.Name)
+	}
+}
+
+// RedactType redacts parameters of the same type as the passed example value.
+func RedactType(example interface{}) RedactionRule {
+	t := reflect.TypeOf(example)
+	return func(arg driver.NamedValue) bool {
+		return arg.Value != nil && reflect.TypeOf(arg.Value) == t
+	}
+}
+
+// RedactAll redacts every parameter.
+func RedactAll(driver.NamedValue) bool {
+	return true
+}
+
+// NewQueryLog that logs every query at debug level, queries slower than the threshold as warnings,
+// and redacts parameters whose names match DefaultRedactedNames.
+func NewQueryLog(slowThreshold time.Duration) *QueryLog {
+	return &QueryLog{
+		Level:         slog.LevelDebug,
+		SlowThreshold: slowThreshold,
+		SlowLevel:     slog.LevelWarn,
+		Redact:        []RedactionRule{RedactName(DefaultRedactedNames)},
+	}
+}
+
+// QueryLog configures the logging of each executed query, with its parameters and duration, to the
+// configured Logger.
+type QueryLog struct {
+	// Level at which queries are logged
+	Level slog.Level
+	// SlowThreshold at or above which queries are logged at the SlowLevel. Zero disables it.
+	SlowThreshold time.Duration
+	// SlowLevel at which slow queries are logged
+	SlowLevel slog.Level
+	// OnlySlow queries are logged
+	OnlySlow bool
+	// RawSQL is logged instead of the normalised query, which omits any literal values
+	RawSQL bool
+	// Redact the values of parameters which match any of these rules
+	Redact []RedactionRule
+}
+
+// redacted reports whether the parameter's value must not be logged.
+func (q *QueryLog) redacted(arg driver.NamedValue) bool {
+	for _, rule := range q.Redact {
+		if rule(arg) {
+			return true
+		}
+	}
+	return false
+}
+
+// logQuery to the connection's logger, if a query log is configured.
+func (r *Connection) logQuery(ctx context.Context, query string, args []driver.NamedValue, duration time.Duration, output *rdsdata.ExecuteStatementOutput, err error) {
+	q := r.queryLog
+	if q == nil || r.log == nil {
+		return
+	}
+	slow := q.SlowThreshold > 0 && duration >= q.SlowThreshold
+	if q.OnlySlow && !slow {
+		return
+	}
+	level, msg := q.Level, "rds: query"
+	if slow {
+		level, msg = q.SlowLevel, "rds: slow query"
+	}
+
+	normalized := NormalizeQuery(r.dialect, query)
+	sql := normalized
+	if q.RawSQL {
+		sql = query
+	}
+	attrs := []slog.Attr{
+		slog.String("sql", sql),
+		slog.String("fingerprint", fingerprint(normalized)),
+		slog.Duration("duration", duration),
+	}
+	if len(args) > 0 {
+		params := make([]any, 0, len(args))
+		for _, arg := range args {
+			key := arg.Name
+			if key == "" {
+				key = strconv.Itoa(arg.Ordinal)
+			}
+			var value any = arg.Value
+			if q.redacted(arg) {
+				value = Redacted
+			}
+			params = append(params, slog.Any(key, value))
+		}
+		attrs = append(attrs, slog.Group("params", params...))
+	}
+	if r.tx != nil {
+		attrs = append(attrs, slog.String("transaction_id", aws.ToString(r.tx.TransactionID)))
+	}
+	if output != nil {
+		attrs = append(attrs,
+			slog.Int("records", len(output.Records)),
+			slog.Int64("rows_affected", output.NumberOfRecordsUpdated))
+	}
+	if err != nil {
+		attrs = append(attrs, slog.Any("error", err))
+	}
+	r.log.log(ctx, level, msg, attrs...)
+}
+
+var (
+	normalizeNumbers    = regexp.MustCompile(`\b\d+(?:\.\d+)?(?:[eE][-+]?\d+)?\b`)
+	normalizeLists      = regexp.MustCompile(`\(\s*\?(?:\s*,\s*\?)+\s*\)`)
+	normalizeWhitespace = regexp.MustCompile(`\s+`)
+)
+
+// NormalizeQuery replaces literal values in the query with placeholders, collapses lists of them,
+// and strips comments and redundant whitespace, so that queries differing only in their values
+// normalise to the same text. Literals and comments are those of the dialect, defaulting to MySQL's.
+// Double quoted strings are treated as literals, so postgres quoted identifiers are replaced as well.
+func NormalizeQuery(dialect Dialect, query string) string {
+	query = syntaxOf(dialect).transform(query, func(literal string) string {
+		switch literal[0] {
+		case '`':
+			return literal
+		case '-', '#', '/':
+			return " "
+		}
+		return "?"
+	}, func(text string) string {
+		return normalizeNumbers.ReplaceAllString(text, "?")
+	})
+	query = normalizeLists.ReplaceAllString(query, "(?+)")
+	query = normalizeWhitespace.ReplaceAllString(query, " ")
+	return strings.TrimSpace(query)
+}
+
+// FingerprintQuery identifies queries which differ only in their literal values, as normalised by NormalizeQuery.
+func FingerprintQuery(dialect Dialect, query string) string {
+	return fingerprint(NormalizeQuery(dialect, query))
+}
+
+func fingerprint(normalized string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(normalized)))
+	return hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,146 @@
+package rds_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"log/slog"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/rdsdata"
+	"github.com/golang/mock/gomock"
+	"github.com/krotscheck/go-rds-driver"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func Test_QueryLog(t *testing.T) {
+	ctx := context.Background()
+
+	Convey("NormalizeQuery", t, func() {
+		mysql := rds.NewMySQL(rds.NewConfig("resourceARN", "secretARN", "database", "region"))
+		postgres := rds.NewPostgres(rds.NewConfig("resourceARN", "secretARN", "database", "region"))
+		cases := [][2]string{
+			{"SELECT * FROM a WHERE id = 1", "SELECT * FROM a WHERE id = ?"},
+			{"SELECT * FROM a WHERE name = 'it''s' AND price > 1.5e3", "SELECT * FROM a WHERE name = ? AND price > ?"},
+			{"/* tagged */ SELECT *\n  FROM a -- trailing\nWHERE id IN (1, 2, 3)", "SELECT * FROM a WHERE id IN (?+)"},
+			{"SELECT * FROM table2 WHERE id = :id", "SELECT * FROM table2 WHERE id = :id"},
+			{"SELECT '--secret' , 1", "SELECT ? , ?"},
+			{"SELECT 'a/*b', \"c -- d\" /* 'e */ FROM `t--1`", "SELECT ?, ? FROM `t--1`"},
+		}
+		for _, c := range cases {
+			So(rds.NormalizeQuery(mysql, c[0]), ShouldEqual, c[1])
+		}
+		So(rds.NormalizeQuery(mysql, `SELECT 'a\'--b' # c`), ShouldEqual, "SELECT ?")
+		So(rds.NormalizeQuery(postgres, "SELECT $q$--secret$q$, 'a/*b' /* /* c */ */"), ShouldEqual, "SELECT ?, ?")
+
+		So(rds.FingerprintQuery(mysql, "SELECT * FROM a WHERE id = 1"), ShouldEqual, rds.FingerprintQuery(mysql, "select * from a where id = 2"))
+		So(rds.FingerprintQuery(mysql, "SELECT * FROM a WHERE id = 1"), ShouldNotEqual, rds.FingerprintQuery(mysql, "SELECT * FROM b WHERE id = 1"))
+		So(len(rds.FingerprintQuery(mysql, "SELECT 1")), ShouldEqual, 16)
+	})
+
+	Convey("QueryLog", t, func() {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		mockRDS := NewMockAWSClientInterface(ctrl)
+		logger := &recordingLogger{}
+		conf := rds.NewConfig("resourceARN", "secretARN", "database", "region")
+		conf.Logger = logger
+		conf.QueryLog = rds.NewQueryLog(time.Hour)
+		conf.QueryLog.Redact = []rds.RedactionRule{rds.RedactName(regexp.MustCompile("^password$")), rds.RedactType([]byte{})}
+		query := "UPDATE users SET password = :password, api_key = :key, email = :email WHERE id = 5"
+		args := []driver.NamedValue{
+			{Name: "password", Value: "hunter2"},
+			{Name: "key", Value: []byte("abc")},
+			{Name: "email", Value: "user@example.com"},
+		}
+		ExpectStatement(mockRDS, query).Return(&rdsdata.ExecuteStatementOutput{NumberOfRecordsUpdated: 1}, nil)
+
+		Convey("Redacts parameters", func() {
+			conn := rds.NewConnection(ctx, mockRDS, conf, rds.NewMySQL(conf)).(*rds.Connection)
+			_, err := conn.ExecContext(ctx, query, args)
+			So(err, ShouldBeNil)
+
+			So(logger.messages(), ShouldResemble, []string{"rds: query"})
+			event := logger.events[0]
+			So(event.level, ShouldEqual, slog.LevelDebug)
+			So(event.attrs["sql"].String(), ShouldEqual, "UPDATE users SET password = :password, api_key = :key, email = :email WHERE id = ?")
+			So(event.attrs["fingerprint"].String(), ShouldEqual, rds.FingerprintQuery(rds.NewMySQL(conf), query))
+			So(event.attrs["rows_affected"].Int64(), ShouldEqual, 1)
+
+			params := map[string]string{}
+			for _, attr := range event.attrs["params"].Group() {
+				params[attr.Key] = attr.Value.String()
+			}
+			So(params, ShouldResemble, map[string]string{
+				"password": rds.Redacted,
+				"key":      rds.Redacted,
+				"email":    "user@example.com",
+			})
+		})
+
+		Convey("Logs slow queries", func() {
+			conf.QueryLog.SlowThreshold = time.Nanosecond
+			conf.QueryLog.RawSQL = true
+			conf.QueryLog.Redact = []rds.RedactionRule{rds.RedactName(regexp.MustCompile("^email$"))}
+			conn := rds.NewConnection(ctx, mockRDS, conf, rds.NewMySQL(conf)).(*rds.Connection)
+			_, err := conn.ExecContext(ctx, query, args)
+			So(err, ShouldBeNil)
+
+			So(logger.messages(), ShouldResemble, []string{"rds: slow query"})
+			event := logger.events[0]
+			So(event.level, ShouldEqual, slog.LevelWarn)
+			So(event.attrs["sql"].String(), ShouldEqual, query)
+			params := event.attrs["params"].Group()
+			So(params[0].Value.String(), ShouldEqual, "hunter2")
+			So(params[2].Value.String(), ShouldEqual, rds.Redacted)
+		})
+
+		Convey("Logs only slow queries", func() {
+			conf.QueryLog.OnlySlow = true
+			conn := rds.NewConnection(ctx, mockRDS, conf, rds.NewMySQL(conf)).(*rds.Connection)
+			_, err := conn.ExecContext(ctx, query, args)
+			So(err, ShouldBeNil)
+			So(logger.messages(), ShouldBeEmpty)
+		})
+	})
+
+	Convey("DSN", t, func() {
+		conf, err := rds.NewConfigFromDSN("rds://?resource_arn=resourceARN&query_log=true&slow_query_threshold=250ms")
+		So(err, ShouldBeNil)
+		So(conf.QueryLog, ShouldNotBeNil)
+		So(conf.QueryLog.SlowThreshold, ShouldEqual, 250*time.Millisecond)
+
+		conf1, err := rds.NewConfigFromDSN(conf.ToDSN())
+		So(err, ShouldBeNil)
+		So(conf1.QueryLog.SlowThreshold, ShouldEqual, 250*time.Millisecond)
+
+		conf, err = rds.NewConfigFromDSN("rds://?resource_arn=resourceARN&slow_query_threshold=250ms")
+		So(err, ShouldBeNil)
+		So(conf.QueryLog, ShouldBeNil)
+	})
+}
+
+func FuzzNormalizeQuery(f *testing.F) {
+	for _, query := range queryFixtures {
+		for _, literal := range literalFixtures {
+			f.Add(literal, query)
+		}
+	}
+	dialect := rds.NewMySQL(rds.NewConfig("resourceARN", "secretARN", "database", "region"))
+	plain := mysqlLiterals("x")
+
+	f.Fuzz(func(t *testing.T, literal string, query string) {
+		for i, quoted := range mysqlLiterals(literal) {
+			if strings.HasPrefix(quoted, "`") {
+				// Identifiers aren't normalised.
+				continue
+			}
+			normalized := rds.NormalizeQuery(dialect, quoted+" "+query)
+			if expected := rds.NormalizeQuery(dialect, plain[i]+" "+query); normalized != expected {
+				t.Fatalf("%q normalised to %q, which depends on its literal", quoted+" "+query, normalized)
+			}
+		}
+	})
+}
@@ -0,0 +1,206 @@
+// Package rdsotel adapts the driver's Hooks to OpenTelemetry, recording a client span for each
+// request to the Data API following the database semantic conventions.
+package rdsotel
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/krotscheck/go-rds-driver"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.40.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ScopeName of the tracer used to record spans.
+const ScopeName = "github.com/krotscheck/go-rds-driver/rdsotel"
+
+var _ rds.Hooks = (*Hooks)(nil) // explicit compile time type check
+
+// Option configures the Hooks.
+type Option func(*Hooks)
+
+// WithTracerProvider used to create the tracer. Defaults to the global TracerProvider.
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(h *Hooks) {
+		h.provider = provider
+	}
+}
+
+// WithAttributes added to every span, such as the name of the calling service.
+func WithAttributes(attrs ...attribute.KeyValue) Option {
+	return func(h *Hooks) {
+		h.attrs = append(h.attrs, attrs...)
+	}
+}
+
+// NewHooks that record spans with the configured tracer.
+func NewHooks(opts ...Option) *Hooks {
+	h := &Hooks{}
+	for _, opt := range opts {
+		opt(h)
+	}
+	if h.provider == nil {
+		h.provider = otel.GetTracerProvider()
+	}
+	h.tracer = h.provider.Tracer(ScopeName)
+	return h
+}
+
+// Hooks recording a span for every statement, transaction operation and wakeup.
+type Hooks struct {
+	provider trace.TracerProvider
+	tracer   trace.Tracer
+	attrs    []attribute.KeyValue
+}
+
+// BeforeStatement starts a span named after the statement's operation.
+func (h *Hooks) BeforeStatement(ctx context.Context, event *rds.StatementEvent) context.Context {
+	query := aws.ToString(event.Input.Sql)
+	operation := operationName(query)
+	attrs := []attribute.KeyValue{
+		dbSystem(event.Dialect),
+		semconv.DBNamespace(aws.ToString(event.Input.Database)),
+		semconv.DBQueryText(query),
+		semconv.CloudResourceID(aws.ToString(event.Input.ResourceArn)),
+	}
+	if operation != "" {
+		attrs = append(attrs, semconv.DBOperationName(operation))
+	}
+	if event.Input.TransactionId != nil {
+		attrs = append(attrs, TransactionIDKey.String(aws.ToString(event.Input.TransactionId)))
+	}
+	return h.start(ctx, spanName(operation, event.Dialect), attrs)
+}
+
+// AfterStatement ends the span, recording the number of rows returned and the request ID.
+func (h *Hooks) AfterStatement(ctx context.Context, event *rds.StatementEvent, err error) {
+	span := trace.SpanFromContext(ctx)
+	if event.Output != nil {
+		span.SetAttributes(semconv.DBResponseReturnedRows(len(event.Output.Records)))
+		if requestID, ok := awsmiddleware.GetRequestIDMetadata(event.Output.ResultMetadata); ok {
+			span.SetAttributes(semconv.AWSRequestID(requestID))
+		}
+	}
+	end(span, err)
+}
+
+// BeforeTransaction starts a span named after the transaction operation.
+func (h *Hooks) BeforeTransaction(ctx context.Context, event *rds.TransactionEvent) context.Context {
+	attrs := []attribute.KeyValue{
+		dbSystem(event.Dialect),
+		semconv.DBNamespace(event.Database),
+		semconv.DBOperationName(string(event.Op)),
+		semconv.CloudResourceID(event.ResourceArn),
+	}
+	if event.TransactionID != "" {
+		attrs = append(attrs, TransactionIDKey.String(event.TransactionID))
+	}
+	return h.start(ctx, string(event.Op), attrs)
+}
+
+// AfterTransaction ends the span, recording the transaction ID and status.
+func (h *Hooks) AfterTransaction(ctx context.Context, event *rds.TransactionEvent, err error) {
+	span := trace.SpanFromContext(ctx)
+	if event.TransactionID != "" {
+		span.SetAttributes(TransactionIDKey.String(event.TransactionID))
+	}
+	if event.Status != "" {
+		span.SetAttributes(TransactionStatusKey.String(event.Status))
+	}
+	end(span, err)
+}
+
+// BeforeWakeup starts a span covering all attempts at waking up the cluster.
+func (h *Hooks) BeforeWakeup(ctx context.Context, event *rds.WakeupEvent) context.Context {
+	return h.start(ctx, "WAKEUP", []attribute.KeyValue{
+		semconv.DBNamespace(event.Database),
+		semconv.CloudResourceID(event.ResourceArn),
+	})
+}
+
+// AfterWakeup ends the span, recording the number of attempts and the detected database.
+func (h *Hooks) AfterWakeup(ctx context.Context, event *rds.WakeupEvent, err error) {
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(WakeupAttemptsKey.Int(event.Attempts))
+	if event.Dialect != nil {
+		span.SetAttributes(dbSystem(event.Dialect))
+	}
+	if event.Version != "" {
+		span.SetAttributes(DBVersionKey.String(event.Version))
+	}
+	end(span, err)
+}
+
+const (
+	// TransactionIDKey of the Data API transaction a span belongs to
+	TransactionIDKey = attribute.Key("aws.rds_data.transaction_id")
+	// TransactionStatusKey returned by the Data API on commit or rollback
+	TransactionStatusKey = attribute.Key("aws.rds_data.transaction_status")
+	// WakeupAttemptsKey made at waking up the cluster
+	WakeupAttemptsKey = attribute.Key("aws.rds_data.wakeup_attempts")
+	// DBVersionKey reported by the cluster once awake
+	DBVersionKey = attribute.Key("aws.rds_data.db_version")
+)
+
+func (h *Hooks) start(ctx context.Context, name string, attrs []attribute.KeyValue) context.Context {
+	ctx, _ = h.tracer.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+		trace.WithAttributes(h.attrs...))
+	return ctx
+}
+
+func end(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		span.SetAttributes(semconv.ErrorType(err))
+		var responseErr interface{ ServiceRequestID() string }
+		if errors.As(err, &responseErr) {
+			span.SetAttributes(semconv.AWSRequestID(responseErr.ServiceRequestID()))
+		}
+	}
+	span.End()
+}
+
+// dbSystem identifies the database from the dialect.
+func dbSystem(dialect rds.Dialect) attribute.KeyValue {
+	switch dialect.(type) {
+	case *rds.DialectMySQL, *rds.DialectMySQL8:
+		return semconv.DBSystemNameMySQL
+	case *rds.DialectPostgres:
+		return semconv.DBSystemNamePostgreSQL
+	}
+	return semconv.DBSystemNameOtherSQL
+}
+
+// spanName of a statement, falling back to the database system should the operation be unknown.
+func spanName(operation string, dialect rds.Dialect) string {
+	if operation != "" {
+		return operation
+	}
+	return dbSystem(dialect).Value.AsString()
+}
+
+// operationName is the first keyword of the query, skipping any leading comments.
+func operationName(query string) string {
+	query = strings.TrimSpace(query)
+	for strings.HasPrefix(query, "/*") {
+		end := strings.Index(query, "*/")
+		if end < 0 {
+			return ""
+		}
+		query = strings.TrimSpace(query[end+2:])
+	}
+	fields := strings.Fields(query)
+	if len(fields) == 0 {
+		return ""
+	}
+	return strings.ToUpper(strings.TrimRight(fields[0], ";("))
+}
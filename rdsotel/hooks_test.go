@@ -0,0 +1,125 @@
+package rdsotel_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rdsdata"
+	"github.com/aws/aws-sdk-go-v2/service/rdsdata/types"
+	"github.com/krotscheck/go-rds-driver"
+	"github.com/krotscheck/go-rds-driver/rdsotel"
+	. "github.com/smartystreets/goconvey/convey"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// stubClient answers every request to the Data API as a MySQL cluster would, failing statements
+// which select from a missing table.
+type stubClient struct{}
+
+func (stubClient) ExecuteStatement(_ context.Context, input *rdsdata.ExecuteStatementInput, _ ...func(*rdsdata.Options)) (*rdsdata.ExecuteStatementOutput, error) {
+	if aws.ToString(input.Sql) == "SELECT * FROM missing" {
+		return nil, &types.BadRequestException{Message: aws.String("Table 'database.missing' doesn't exist")}
+	}
+	return &rdsdata.ExecuteStatementOutput{
+		ColumnMetadata: []types.ColumnMetadata{{Label: aws.String("version"), TypeName: aws.String("VARCHAR")}},
+		Records:        [][]types.Field{{&types.FieldMemberStringValue{Value: "5.7.0"}}},
+	}, nil
+}
+
+func (stubClient) BeginTransaction(context.Context, *rdsdata.BeginTransactionInput, ...func(*rdsdata.Options)) (*rdsdata.BeginTransactionOutput, error) {
+	return &rdsdata.BeginTransactionOutput{TransactionId: aws.String("transactionID")}, nil
+}
+
+func (stubClient) CommitTransaction(context.Context, *rdsdata.CommitTransactionInput, ...func(*rdsdata.Options)) (*rdsdata.CommitTransactionOutput, error) {
+	return &rdsdata.CommitTransactionOutput{TransactionStatus: aws.String("Transaction Committed")}, nil
+}
+
+func (stubClient) RollbackTransaction(context.Context, *rdsdata.RollbackTransactionInput, ...func(*rdsdata.Options)) (*rdsdata.RollbackTransactionOutput, error) {
+	return &rdsdata.RollbackTransactionOutput{TransactionStatus: aws.String("Rollback Complete")}, nil
+}
+
+func attributes(span tracetest.SpanStub) map[attribute.Key]attribute.Value {
+	attrs := map[attribute.Key]attribute.Value{}
+	for _, attr := range span.Attributes {
+		attrs[attr.Key] = attr.Value
+	}
+	return attrs
+}
+
+func Test_Hooks(t *testing.T) {
+	ctx := context.Background()
+
+	Convey("Hooks", t, func() {
+		exporter := tracetest.NewInMemoryExporter()
+		provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+		conf := rds.NewConfig("resourceARN", "secretARN", "database", "region")
+		conf.Hooks = rdsotel.NewHooks(
+			rdsotel.WithTracerProvider(provider),
+			rdsotel.WithAttributes(attribute.String("service.name", "accounts")),
+		)
+		db := sql.OpenDB(rds.NewConnector(rds.NewDriver(), stubClient{}, conf))
+		defer db.Close()
+
+		ctx, parent := provider.Tracer("test").Start(ctx, "parent")
+		tx, err := db.BeginTx(ctx, nil)
+		So(err, ShouldBeNil)
+		_, err = tx.ExecContext(ctx, "/* tagged */ UPDATE accounts SET balance = 0")
+		So(err, ShouldBeNil)
+		So(tx.Commit(), ShouldBeNil)
+		_, err = db.QueryContext(ctx, "SELECT * FROM missing")
+		So(err, ShouldNotBeNil)
+		parent.End()
+
+		spans := exporter.GetSpans()
+		var names []string
+		for _, span := range spans {
+			names = append(names, span.Name)
+		}
+		So(names, ShouldResemble, []string{"WAKEUP", "BEGIN", "UPDATE", "COMMIT", "SELECT", "parent"})
+
+		Convey("Wakeup", func() {
+			attrs := attributes(spans[0])
+			So(attrs[rdsotel.WakeupAttemptsKey].AsInt64(), ShouldEqual, 1)
+			So(attrs[rdsotel.DBVersionKey].AsString(), ShouldEqual, "5.7.0")
+			So(attrs["db.system.name"].AsString(), ShouldEqual, "mysql")
+		})
+
+		Convey("Statements", func() {
+			update := spans[2]
+			So(update.SpanKind, ShouldEqual, trace.SpanKindClient)
+			So(update.Parent.SpanID(), ShouldEqual, parent.SpanContext().SpanID())
+
+			attrs := attributes(update)
+			So(attrs["db.system.name"].AsString(), ShouldEqual, "mysql")
+			So(attrs["db.namespace"].AsString(), ShouldEqual, "database")
+			So(attrs["db.operation.name"].AsString(), ShouldEqual, "UPDATE")
+			So(attrs["db.query.text"].AsString(), ShouldEqual, "/* tagged */ UPDATE accounts SET balance = 0")
+			So(attrs["service.name"].AsString(), ShouldEqual, "accounts")
+			So(attrs[rdsotel.TransactionIDKey].AsString(), ShouldEqual, "transactionID")
+		})
+
+		Convey("Transactions", func() {
+			attrs := attributes(spans[3])
+			So(attrs["db.operation.name"].AsString(), ShouldEqual, "COMMIT")
+			So(attrs[rdsotel.TransactionIDKey].AsString(), ShouldEqual, "transactionID")
+			So(attrs[rdsotel.TransactionStatusKey].AsString(), ShouldEqual, "Transaction Committed")
+		})
+
+		Convey("Errors", func() {
+			failed := spans[4]
+			So(failed.Status.Code, ShouldEqual, codes.Error)
+			So(len(failed.Events), ShouldEqual, 1)
+			var badRequest *types.BadRequestException
+			So(errors.As(err, &badRequest), ShouldBeTrue)
+			So(attributes(failed)["error.type"].AsString(), ShouldEqual, "*types.BadRequestException")
+		})
+	})
+}
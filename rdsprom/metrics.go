@@ -0,0 +1,136 @@
+// Package rdsprom adapts the driver's Metrics to Prometheus collectors.
+package rdsprom
+
+import (
+	"time"
+
+	"github.com/krotscheck/go-rds-driver"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var _ rds.Metrics = (*Metrics)(nil)          // explicit compile time type check
+var _ prometheus.Collector = (*Metrics)(nil) // explicit compile time type check
+
+// NewMetrics with the passed namespace, such as "myservice". Register them with a
+// prometheus.Registerer before setting them on an rds.Config.
+func NewMetrics(namespace string) *Metrics {
+	return &Metrics{
+		calls: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "rds_data_api",
+			Name:      "calls_total",
+			Help:      "Calls to the Data API, by call and result.",
+		}, []string{"call", "result"}),
+		callDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "rds_data_api",
+			Name:      "call_duration_seconds",
+			Help:      "Latency of calls to the Data API.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"call"}),
+		records: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "rds_data_api",
+			Name:      "records_returned",
+			Help:      "Records returned by each statement.",
+			Buckets:   []float64{0, 1, 10, 100, 1000, 10000},
+		}),
+		responseSize: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "rds_data_api",
+			Name:      "response_bytes",
+			Help:      "Approximate size of the values returned by each statement.",
+			Buckets:   []float64{1024, 4096, 32768, 131072, 1048576},
+		}),
+		retries: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "rds_data_api",
+			Name:      "wakeup_retries_total",
+			Help:      "Retried requests to wake up the cluster.",
+		}),
+		wakeupDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "rds_data_api",
+			Name:      "wakeup_duration_seconds",
+			Help:      "Time taken to wake up the cluster, by result.",
+			Buckets:   []float64{0.1, 0.5, 1, 2.5, 5, 10, 30, 60},
+		}, []string{"result"}),
+		transactions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "rds_data_api",
+			Name:      "transactions_total",
+			Help:      "Completed transactions, by outcome.",
+		}, []string{"outcome"}),
+	}
+}
+
+// Metrics collects the driver's usage of the Data API as Prometheus metrics.
+type Metrics struct {
+	calls          *prometheus.CounterVec
+	callDuration   *prometheus.HistogramVec
+	records        prometheus.Histogram
+	responseSize   prometheus.Histogram
+	retries        prometheus.Counter
+	wakeupDuration *prometheus.HistogramVec
+	transactions   *prometheus.CounterVec
+}
+
+func (m *Metrics) collectors() []prometheus.Collector {
+	return []prometheus.Collector{
+		m.calls,
+		m.callDuration,
+		m.records,
+		m.responseSize,
+		m.retries,
+		m.wakeupDuration,
+		m.transactions,
+	}
+}
+
+// Describe all metrics.
+func (m *Metrics) Describe(ch chan<- *prometheus.Desc) {
+	for _, collector := range m.collectors() {
+		collector.Describe(ch)
+	}
+}
+
+// Collect all metrics.
+func (m *Metrics) Collect(ch chan<- prometheus.Metric) {
+	for _, collector := range m.collectors() {
+		collector.Collect(ch)
+	}
+}
+
+// ObserveCall to the Data API.
+func (m *Metrics) ObserveCall(call string, duration time.Duration, err error) {
+	m.calls.WithLabelValues(call, result(err)).Inc()
+	m.callDuration.WithLabelValues(call).Observe(duration.Seconds())
+}
+
+// ObserveResult of a statement.
+func (m *Metrics) ObserveResult(records int, size int) {
+	m.records.Observe(float64(records))
+	m.responseSize.Observe(float64(size))
+}
+
+// ObserveRetry of a failed request to wake up the cluster.
+func (m *Metrics) ObserveRetry(error) {
+	m.retries.Inc()
+}
+
+// ObserveWakeup of the cluster.
+func (m *Metrics) ObserveWakeup(duration time.Duration, _ int, err error) {
+	m.wakeupDuration.WithLabelValues(result(err)).Observe(duration.Seconds())
+}
+
+// ObserveTransaction once it completes.
+func (m *Metrics) ObserveTransaction(outcome rds.TransactionOutcome) {
+	m.transactions.WithLabelValues(string(outcome)).Inc()
+}
+
+func result(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "ok"
+}
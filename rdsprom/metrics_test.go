@@ -0,0 +1,59 @@
+package rdsprom_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/krotscheck/go-rds-driver"
+	"github.com/krotscheck/go-rds-driver/rdsprom"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func Test_Metrics(t *testing.T) {
+	Convey("Metrics", t, func() {
+		metrics := rdsprom.NewMetrics("test")
+		registry := prometheus.NewPedanticRegistry()
+		So(registry.Register(metrics), ShouldBeNil)
+
+		metrics.ObserveCall(rds.CallExecuteStatement, 20*time.Millisecond, nil)
+		metrics.ObserveCall(rds.CallExecuteStatement, time.Second, errors.New("failed"))
+		metrics.ObserveCall(rds.CallCommitTransaction, time.Millisecond, nil)
+		metrics.ObserveResult(3, 2048)
+		metrics.ObserveRetry(errors.New("resuming"))
+		metrics.ObserveWakeup(2*time.Second, 2, nil)
+		metrics.ObserveTransaction(rds.TransactionCommitted)
+		metrics.ObserveTransaction(rds.TransactionExpired)
+
+		expected := `
+# HELP test_rds_data_api_calls_total Calls to the Data API, by call and result.
+# TYPE test_rds_data_api_calls_total counter
+test_rds_data_api_calls_total{call="CommitTransaction",result="ok"} 1
+test_rds_data_api_calls_total{call="ExecuteStatement",result="error"} 1
+test_rds_data_api_calls_total{call="ExecuteStatement",result="ok"} 1
+# HELP test_rds_data_api_transactions_total Completed transactions, by outcome.
+# TYPE test_rds_data_api_transactions_total counter
+test_rds_data_api_transactions_total{outcome="committed"} 1
+test_rds_data_api_transactions_total{outcome="expired"} 1
+# HELP test_rds_data_api_wakeup_retries_total Retried requests to wake up the cluster.
+# TYPE test_rds_data_api_wakeup_retries_total counter
+test_rds_data_api_wakeup_retries_total 1
+`
+		So(testutil.GatherAndCompare(registry, strings.NewReader(expected),
+			"test_rds_data_api_calls_total",
+			"test_rds_data_api_transactions_total",
+			"test_rds_data_api_wakeup_retries_total",
+		), ShouldBeNil)
+
+		count, err := testutil.GatherAndCount(registry,
+			"test_rds_data_api_call_duration_seconds",
+			"test_rds_data_api_records_returned",
+			"test_rds_data_api_response_bytes",
+			"test_rds_data_api_wakeup_duration_seconds")
+		So(err, ShouldBeNil)
+		So(count, ShouldEqual, 5)
+	})
+}
@@ -0,0 +1,80 @@
+package rdstest
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/service/rdsdata"
+	"github.com/aws/aws-sdk-go-v2/service/rdsdata/types"
+	"github.com/aws/smithy-go"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	"github.com/krotscheck/go-rds-driver/internal/dataapi"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// sqlState of the PostgreSQL errors emulated for SQLite's constraint violations.
+var sqlState = map[int]string{
+	sqlite3.SQLITE_CONSTRAINT_CHECK:      "23514",
+	sqlite3.SQLITE_CONSTRAINT_UNIQUE:     "23505",
+	sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY: "23505",
+	sqlite3.SQLITE_CONSTRAINT_NOTNULL:    "23502",
+	sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY: "23503",
+}
+
+// serviceError wraps the error returned by the Data API as the SDK would, with the operation which
+// failed and, for exceptions, the ID of the request.
+func (f *Fake) serviceError(operation string, err error) error {
+	if err == nil {
+		return nil
+	}
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) {
+		return &smithy.OperationError{ServiceID: rdsdata.ServiceID, OperationName: operation, Err: err}
+	}
+	status := http.StatusBadRequest
+	var notFound *types.TransactionNotFoundException
+	if errors.As(err, &notFound) {
+		status = http.StatusNotFound
+	}
+	return responseError(operation, err, status, dataapi.NewRequestID())
+}
+
+// responseError wrapping an exception of the Data API, as the SDK would for a response of the given status.
+func responseError(operation string, err error, status int, requestID string) error {
+	return &smithy.OperationError{
+		ServiceID:     rdsdata.ServiceID,
+		OperationName: operation,
+		Err: &awshttp.ResponseError{
+			ResponseError: &smithyhttp.ResponseError{
+				Response: &smithyhttp.Response{Response: &http.Response{StatusCode: status}},
+				Err:      err,
+			},
+			RequestID: requestID,
+		},
+	}
+}
+
+// message of an error returned by SQLite, as the emulated database would word it.
+func (f *Fake) message(err error) string {
+	var sqliteErr *sqlite.Error
+	if !errors.As(err, &sqliteErr) {
+		return err.Error()
+	}
+	code := sqliteErr.Code()
+	if primary := code & 0xff; primary == sqlite3.SQLITE_BUSY || primary == sqlite3.SQLITE_LOCKED {
+		if f.engine.Postgres {
+			return "ERROR: canceling statement due to lock timeout; SQLState: 55P03"
+		}
+		return "Lock wait timeout exceeded; try restarting transaction"
+	}
+	if !f.engine.Postgres {
+		return sqliteErr.Error()
+	}
+	if state, ok := sqlState[code]; ok {
+		return fmt.Sprintf("ERROR: %s; SQLState: %s", sqliteErr.Error(), state)
+	}
+	return "ERROR: " + sqliteErr.Error()
+}
@@ -0,0 +1,143 @@
+// Package rdstest provides an in-process fake of the Data API, backed by an in-memory SQLite database,
+// so that code using the driver may be tested without an Aurora cluster, and a recorder and replayer of the
+// interactions with a real one.
+package rdstest
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync/atomic"
+
+	"github.com/aws/aws-sdk-go-v2/service/rdsdata"
+	"github.com/aws/aws-sdk-go-v2/service/rdsdata/types"
+	"github.com/krotscheck/go-rds-driver"
+	"github.com/krotscheck/go-rds-driver/internal/dataapi"
+	_ "modernc.org/sqlite" // registers the sqlite driver
+)
+
+var _ rds.AWSClientInterface = (*Fake)(nil) // explicit compile time type check
+
+// DefaultVersion returned by the fake when the driver wakes up the cluster, selecting the MySQL 8 dialect.
+const DefaultVersion = "8.0.28"
+
+// Transaction statuses returned by the Data API.
+const (
+	StatusCommitted  = dataapi.StatusCommitted
+	StatusRolledBack = dataapi.StatusRolledBack
+)
+
+// versionQuery issued by the driver to wake up the cluster and detect its dialect.
+var versionQuery = regexp.MustCompile(`(?is)^\s*(/\*.*?\*/\s*)*select\s+version\(\)\s*;?\s*$`)
+
+// setTransaction statements issued by the driver at the start of a transaction, which SQLite doesn't support.
+var setTransaction = regexp.MustCompile(`(?is)^\s*(/\*.*?\*/\s*)*set\s+transaction\b`)
+
+// databases counts the fakes, so that each has its own in-memory database.
+var databases atomic.Int64
+
+// Option configures the Fake.
+type Option func(*Fake)
+
+// WithVersion returned by VERSION(), which selects the dialect the driver uses. Versions containing
+// "postgres" also make the fake emulate the PostgreSQL Data API, such as by omitting generated fields.
+func WithVersion(version string) Option {
+	return func(f *Fake) {
+		f.version = version
+	}
+}
+
+// NewFake Data API, backed by a new, empty in-memory database. It panics if the database can't be opened.
+func NewFake(opts ...Option) *Fake {
+	f := &Fake{version: DefaultVersion}
+	for _, opt := range opts {
+		opt(f)
+	}
+
+	db, err := sql.Open("sqlite", fmt.Sprintf("file:/rdstest-%d?vfs=memdb&_pragma=busy_timeout(1000)&_pragma=foreign_keys(1)", databases.Add(1)))
+	if err != nil {
+		panic(err)
+	}
+	// The in-memory database is discarded once its last connection closes, so hold on to one.
+	if f.pinned, err = db.Conn(context.Background()); err != nil {
+		panic(err)
+	}
+	f.db = db
+	f.engine = dataapi.NewEngine(db)
+	f.engine.Postgres = strings.Contains(strings.ToLower(f.version), "postgres")
+	f.engine.Intercept = f.intercept
+	f.engine.Message = f.message
+	return f
+}
+
+// Fake implementation of the Data API. Statements are executed by SQLite, so they must be written in
+// the subset of SQL it shares with the emulated database.
+type Fake struct {
+	version string
+	db      *sql.DB
+	pinned  *sql.Conn
+	engine  *dataapi.Engine
+}
+
+// DB backing the fake, such as to create a schema or seed it without going through the driver.
+func (f *Fake) DB() *sql.DB {
+	return f.db
+}
+
+// OpenTransactions which have been begun but neither committed nor rolled back.
+func (f *Fake) OpenTransactions() int {
+	return f.engine.OpenTransactions()
+}
+
+// Close the fake, rolling back any open transactions and discarding the database.
+func (f *Fake) Close() error {
+	f.engine.Close()
+	_ = f.pinned.Close()
+	return f.db.Close()
+}
+
+// ExecuteStatement against the database, in the given transaction if any.
+func (f *Fake) ExecuteStatement(ctx context.Context, input *rdsdata.ExecuteStatementInput, _ ...func(*rdsdata.Options)) (*rdsdata.ExecuteStatementOutput, error) {
+	output, err := f.engine.ExecuteStatement(ctx, input)
+	return output, f.serviceError("ExecuteStatement", err)
+}
+
+// BatchExecuteStatement once for every set of parameters, in the given transaction if any.
+func (f *Fake) BatchExecuteStatement(ctx context.Context, input *rdsdata.BatchExecuteStatementInput, _ ...func(*rdsdata.Options)) (*rdsdata.BatchExecuteStatementOutput, error) {
+	output, err := f.engine.BatchExecuteStatement(ctx, input)
+	return output, f.serviceError("BatchExecuteStatement", err)
+}
+
+// BeginTransaction returning the ID to pass to subsequent statements.
+func (f *Fake) BeginTransaction(ctx context.Context, input *rdsdata.BeginTransactionInput, _ ...func(*rdsdata.Options)) (*rdsdata.BeginTransactionOutput, error) {
+	output, err := f.engine.BeginTransaction(ctx, input)
+	return output, f.serviceError("BeginTransaction", err)
+}
+
+// CommitTransaction with the given ID.
+func (f *Fake) CommitTransaction(ctx context.Context, input *rdsdata.CommitTransactionInput, _ ...func(*rdsdata.Options)) (*rdsdata.CommitTransactionOutput, error) {
+	output, err := f.engine.CommitTransaction(ctx, input)
+	return output, f.serviceError("CommitTransaction", err)
+}
+
+// RollbackTransaction with the given ID.
+func (f *Fake) RollbackTransaction(ctx context.Context, input *rdsdata.RollbackTransactionInput, _ ...func(*rdsdata.Options)) (*rdsdata.RollbackTransactionOutput, error) {
+	output, err := f.engine.RollbackTransaction(ctx, input)
+	return output, f.serviceError("RollbackTransaction", err)
+}
+
+// intercept the statements issued by the driver which SQLite doesn't support.
+func (f *Fake) intercept(query string) (*rdsdata.ExecuteStatementOutput, bool) {
+	switch {
+	case versionQuery.MatchString(query):
+		return &rdsdata.ExecuteStatementOutput{
+			Records:        [][]types.Field{{&types.FieldMemberStringValue{Value: f.version}}},
+			ColumnMetadata: []types.ColumnMetadata{f.engine.Column("VERSION()", "VARCHAR", true)},
+		}, true
+	case setTransaction.MatchString(query):
+		return &rdsdata.ExecuteStatementOutput{}, true
+	}
+	return nil, false
+}
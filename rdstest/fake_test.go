@@ -0,0 +1,132 @@
+package rdstest_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rdsdata"
+	"github.com/aws/aws-sdk-go-v2/service/rdsdata/types"
+	"github.com/krotscheck/go-rds-driver"
+	"github.com/krotscheck/go-rds-driver/rdstest"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func Test_Fake(t *testing.T) {
+	ctx := context.Background()
+
+	Convey("Fake", t, func() {
+		Convey("MySQL", func() {
+			fake := rdstest.NewFake()
+			defer fake.Close()
+			conf := rds.NewConfig("resourceARN", "secretARN", "database", "region")
+			conf.ParseTime = true
+			db := sql.OpenDB(rds.NewConnector(rds.NewDriver(), fake, conf))
+			defer db.Close()
+
+			_, err := db.ExecContext(ctx, "CREATE TABLE accounts (id INTEGER PRIMARY KEY AUTOINCREMENT, name VARCHAR(255) NOT NULL UNIQUE, balance DECIMAL(10,2), opened DATETIME)")
+			So(err, ShouldBeNil)
+
+			opened := time.Date(2021, 6, 1, 12, 30, 0, 0, time.UTC)
+			result, err := db.ExecContext(ctx, "INSERT INTO accounts (name, balance, opened) VALUES (?, ?, ?)", "alice", 10.5, opened)
+			So(err, ShouldBeNil)
+			id, err := result.LastInsertId()
+			So(err, ShouldBeNil)
+			So(id, ShouldEqual, 1)
+			affected, err := result.RowsAffected()
+			So(err, ShouldBeNil)
+			So(affected, ShouldEqual, 1)
+
+			Convey("Returns records", func() {
+				var name string
+				var balance float64
+				var when time.Time
+				var count int64
+				So(db.QueryRowContext(ctx, "SELECT name, balance, opened, COUNT(*) AS total FROM accounts WHERE id = ?", id).Scan(&name, &balance, &when, &count), ShouldBeNil)
+				So(name, ShouldEqual, "alice")
+				So(balance, ShouldEqual, 10.5)
+				So(when, ShouldEqual, opened)
+				So(count, ShouldEqual, 1)
+			})
+
+			Convey("Commits transactions", func() {
+				tx, err := db.BeginTx(ctx, nil)
+				So(err, ShouldBeNil)
+				_, err = tx.ExecContext(ctx, "UPDATE accounts SET balance = balance + 1")
+				So(err, ShouldBeNil)
+				So(fake.OpenTransactions(), ShouldEqual, 1)
+				So(tx.Commit(), ShouldBeNil)
+				So(fake.OpenTransactions(), ShouldEqual, 0)
+
+				var balance float64
+				So(db.QueryRowContext(ctx, "SELECT balance FROM accounts").Scan(&balance), ShouldBeNil)
+				So(balance, ShouldEqual, 11.5)
+			})
+
+			Convey("Rolls back transactions", func() {
+				tx, err := db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+				So(err, ShouldBeNil)
+				_, err = tx.ExecContext(ctx, "DELETE FROM accounts")
+				So(err, ShouldBeNil)
+				So(tx.Rollback(), ShouldBeNil)
+
+				var count int
+				So(db.QueryRowContext(ctx, "SELECT COUNT(*) FROM accounts").Scan(&count), ShouldBeNil)
+				So(count, ShouldEqual, 1)
+			})
+
+			Convey("Returns errors in the shape of the Data API", func() {
+				_, err := db.ExecContext(ctx, "INSERT INTO accounts (name) VALUES (?)", "alice")
+				var stmtErr *rds.StatementError
+				So(errors.As(err, &stmtErr), ShouldBeTrue)
+				So(stmtErr.RequestID, ShouldNotBeEmpty)
+				var badRequest *types.BadRequestException
+				So(errors.As(err, &badRequest), ShouldBeTrue)
+				So(badRequest.ErrorMessage(), ShouldContainSubstring, "UNIQUE constraint failed")
+			})
+		})
+
+		Convey("PostgreSQL", func() {
+			fake := rdstest.NewFake(rdstest.WithVersion("PostgreSQL 13.9"))
+			defer fake.Close()
+			conf := rds.NewConfig("resourceARN", "secretARN", "database", "region")
+			db := sql.OpenDB(rds.NewConnector(rds.NewDriver(), fake, conf))
+			defer db.Close()
+
+			_, err := fake.DB().ExecContext(ctx, "CREATE TABLE flags (id INTEGER PRIMARY KEY, name TEXT, enabled BOOLEAN, CHECK (name <> ''))")
+			So(err, ShouldBeNil)
+
+			result, err := db.ExecContext(ctx, "INSERT INTO flags (name, enabled) VALUES ($1, $2)", "beta", true)
+			So(err, ShouldBeNil)
+			_, err = result.LastInsertId()
+			So(err, ShouldEqual, rds.ErrLastInsertIDNotSupported)
+
+			var id int64
+			So(db.QueryRowContext(ctx, "INSERT INTO flags (name, enabled) VALUES ($1, $2) RETURNING id", "gamma", false).Scan(&id), ShouldBeNil)
+			So(id, ShouldEqual, 2)
+
+			var enabled bool
+			So(db.QueryRowContext(ctx, "SELECT enabled FROM flags WHERE name = $1", "beta").Scan(&enabled), ShouldBeNil)
+			So(enabled, ShouldBeTrue)
+
+			_, err = db.ExecContext(ctx, "INSERT INTO flags (name) VALUES ($1)", "")
+			So(errors.Is(err, rds.ErrCheckViolation), ShouldBeTrue)
+		})
+
+		Convey("Rejects unknown transactions", func() {
+			fake := rdstest.NewFake()
+			defer fake.Close()
+
+			_, err := fake.CommitTransaction(ctx, &rdsdata.CommitTransactionInput{
+				ResourceArn:   aws.String("resourceARN"),
+				SecretArn:     aws.String("secretARN"),
+				TransactionId: aws.String("missing"),
+			})
+			var notFound *types.TransactionNotFoundException
+			So(errors.As(err, &notFound), ShouldBeTrue)
+		})
+	})
+}
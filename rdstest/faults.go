@@ -0,0 +1,230 @@
+package rdstest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"regexp"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rdsdata"
+	"github.com/aws/aws-sdk-go-v2/service/rdsdata/types"
+	"github.com/aws/smithy-go"
+	"github.com/krotscheck/go-rds-driver"
+	"github.com/krotscheck/go-rds-driver/internal/dataapi"
+)
+
+var _ rds.AWSClientInterface = (*FaultInjector)(nil) // explicit compile time type check
+
+// ErrInjected is returned by rules which drop calls without an error of their own.
+var ErrInjected = fmt.Errorf("injected fault")
+
+// Operations of the Data API, as matched by fault rules.
+const (
+	OperationExecuteStatement    = "ExecuteStatement"
+	OperationBeginTransaction    = "BeginTransaction"
+	OperationCommitTransaction   = "CommitTransaction"
+	OperationRollbackTransaction = "RollbackTransaction"
+)
+
+// DatabaseResuming returns the exception of a cluster which is resuming after being paused.
+func DatabaseResuming() error {
+	return &types.DatabaseResumingException{Message: aws.String("The Aurora DB instance is resuming after being auto-paused. Please wait a few seconds and try again.")}
+}
+
+// Throttling returns the exception of a request which exceeded the rate limit.
+func Throttling() error {
+	return &smithy.GenericAPIError{Code: "ThrottlingException", Message: "Rate exceeded", Fault: smithy.FaultClient}
+}
+
+// DatabaseUnavailable returns the exception of a cluster which can't be reached.
+func DatabaseUnavailable() error {
+	return &types.DatabaseUnavailableException{Message: aws.String("The database is unavailable.")}
+}
+
+// FaultRule injecting a fault into the calls it matches. Calls match a rule if they match all of its
+// conditions.
+type FaultRule struct {
+	// Operation of the calls, such as OperationCommitTransaction. Matches all operations if empty.
+	Operation string
+	// SQL of the statements executed. Rules with a pattern only match calls of ExecuteStatement.
+	SQL *regexp.Regexp
+	// Calls into which the fault is injected, counting from 1 the calls which match the rule's other
+	// conditions. The fault is injected into all of them if empty.
+	Calls []int
+	// Times the fault is injected at most. Unlimited if zero.
+	Times int
+	// Probability, between 0 and 1, of injecting the fault into a call. Always if zero.
+	Probability float64
+
+	// Latency added to the call, which is abandoned should its context be done first.
+	Latency time.Duration
+	// Err returned without passing the call to the client. Exceptions of the Data API are returned in the
+	// shape the SDK returns them in, with a status and request ID.
+	Err error
+	// Drop the call: it's not passed to the client, and Err, or ErrInjected if nil, is returned.
+	Drop bool
+	// LoseResponse of the call: it's passed to the client, but Err, or ErrInjected if nil, is returned
+	// instead of its response, as though the connection failed after the request was sent.
+	LoseResponse bool
+
+	matched  atomic.Int64
+	injected atomic.Int64
+}
+
+// Injected returns the number of calls the fault was injected into.
+func (r *FaultRule) Injected() int {
+	return int(r.injected.Load())
+}
+
+// matches the call, and counts it if so.
+func (r *FaultRule) matches(operation string, sql string) bool {
+	if r.Operation != "" && r.Operation != operation {
+		return false
+	}
+	if r.SQL != nil && (operation != OperationExecuteStatement || !r.SQL.MatchString(sql)) {
+		return false
+	}
+	count := int(r.matched.Add(1))
+	return len(r.Calls) == 0 || slices.Contains(r.Calls, count)
+}
+
+// NewFaultInjector wrapping the client, injecting the faults of the rules into its calls.
+func NewFaultInjector(client rds.AWSClientInterface, rules ...*FaultRule) *FaultInjector {
+	return &FaultInjector{
+		client: client,
+		rules:  rules,
+		random: rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64())),
+	}
+}
+
+// FaultInjector wrapping a client of the Data API, such as to test how code handles its failures. Every
+// call is checked against the rules in order, and the first whose fault fires is injected into it.
+type FaultInjector struct {
+	client rds.AWSClientInterface
+
+	mu     sync.Mutex
+	rules  []*FaultRule
+	random *rand.Rand
+}
+
+// Seed the probabilities of the rules, making them reproducible.
+func (f *FaultInjector) Seed(seed uint64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.random = rand.New(rand.NewPCG(seed, seed))
+}
+
+// AddRule injecting faults into subsequent calls.
+func (f *FaultInjector) AddRule(rule *FaultRule) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.rules = append(f.rules, rule)
+}
+
+// ExecuteStatement with the wrapped client, unless a fault is injected.
+func (f *FaultInjector) ExecuteStatement(ctx context.Context, input *rdsdata.ExecuteStatementInput, optFns ...func(*rdsdata.Options)) (*rdsdata.ExecuteStatementOutput, error) {
+	return inject(ctx, f, OperationExecuteStatement, aws.ToString(input.Sql), func() (*rdsdata.ExecuteStatementOutput, error) {
+		return f.client.ExecuteStatement(ctx, input, optFns...)
+	})
+}
+
+// BeginTransaction with the wrapped client, unless a fault is injected.
+func (f *FaultInjector) BeginTransaction(ctx context.Context, input *rdsdata.BeginTransactionInput, optFns ...func(*rdsdata.Options)) (*rdsdata.BeginTransactionOutput, error) {
+	return inject(ctx, f, OperationBeginTransaction, "", func() (*rdsdata.BeginTransactionOutput, error) {
+		return f.client.BeginTransaction(ctx, input, optFns...)
+	})
+}
+
+// CommitTransaction with the wrapped client, unless a fault is injected.
+func (f *FaultInjector) CommitTransaction(ctx context.Context, input *rdsdata.CommitTransactionInput, optFns ...func(*rdsdata.Options)) (*rdsdata.CommitTransactionOutput, error) {
+	return inject(ctx, f, OperationCommitTransaction, "", func() (*rdsdata.CommitTransactionOutput, error) {
+		return f.client.CommitTransaction(ctx, input, optFns...)
+	})
+}
+
+// RollbackTransaction with the wrapped client, unless a fault is injected.
+func (f *FaultInjector) RollbackTransaction(ctx context.Context, input *rdsdata.RollbackTransactionInput, optFns ...func(*rdsdata.Options)) (*rdsdata.RollbackTransactionOutput, error) {
+	return inject(ctx, f, OperationRollbackTransaction, "", func() (*rdsdata.RollbackTransactionOutput, error) {
+		return f.client.RollbackTransaction(ctx, input, optFns...)
+	})
+}
+
+// fault which fires for the call, if any.
+func (f *FaultInjector) fault(operation string, sql string) *FaultRule {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var fired *FaultRule
+	for _, rule := range f.rules {
+		// Every rule counts the calls it matches, whether or not an earlier one fired.
+		if !rule.matches(operation, sql) || fired != nil {
+			continue
+		}
+		if rule.Times > 0 && rule.Injected() >= rule.Times {
+			continue
+		}
+		if rule.Probability > 0 && f.random.Float64() >= rule.Probability {
+			continue
+		}
+		rule.injected.Add(1)
+		fired = rule
+	}
+	return fired
+}
+
+// inject the fault which fires for the call, if any, into it.
+func inject[T any](ctx context.Context, f *FaultInjector, operation string, sql string, call func() (*T, error)) (*T, error) {
+	rule := f.fault(operation, sql)
+	if rule == nil {
+		return call()
+	}
+	if rule.Latency > 0 {
+		timer := time.NewTimer(rule.Latency)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return nil, &smithy.OperationError{ServiceID: rdsdata.ServiceID, OperationName: operation, Err: ctx.Err()}
+		case <-timer.C:
+		}
+	}
+
+	err := rule.Err
+	if err == nil && (rule.Drop || rule.LoseResponse) {
+		err = ErrInjected
+	}
+	if !rule.Drop && (err == nil || rule.LoseResponse) {
+		output, callErr := call()
+		if err == nil || callErr != nil {
+			return output, callErr
+		}
+	}
+	return nil, injectedError(operation, err)
+}
+
+// injectedError in the shape the SDK returns errors in.
+func injectedError(operation string, err error) error {
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) {
+		return &smithy.OperationError{ServiceID: rdsdata.ServiceID, OperationName: operation, Err: err}
+	}
+	status := http.StatusBadRequest
+	switch apiErr.ErrorCode() {
+	case "ThrottlingException", "TooManyRequestsException":
+		status = http.StatusTooManyRequests
+	case "TransactionNotFoundException", "NotFoundException":
+		status = http.StatusNotFound
+	case "AccessDeniedException", "ForbiddenException":
+		status = http.StatusForbidden
+	case "InternalServerErrorException":
+		status = http.StatusInternalServerError
+	case "ServiceUnavailableError":
+		status = http.StatusServiceUnavailable
+	}
+	return responseError(operation, err, status, dataapi.NewRequestID())
+}
@@ -0,0 +1,74 @@
+package rdstest_test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rdsdata"
+	"github.com/aws/aws-sdk-go-v2/service/rdsdata/types"
+	"github.com/krotscheck/go-rds-driver/rdstest"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func Test_FaultInjector(t *testing.T) {
+	ctx := context.Background()
+
+	Convey("FaultInjector", t, func() {
+		fake := rdstest.NewFake()
+		defer fake.Close()
+		faults := rdstest.NewFaultInjector(fake)
+		execute := func(query string) error {
+			_, err := faults.ExecuteStatement(ctx, &rdsdata.ExecuteStatementInput{
+				ResourceArn: aws.String("resourceARN"),
+				SecretArn:   aws.String("secretARN"),
+				Sql:         aws.String(query),
+			})
+			return err
+		}
+
+		Convey("Injects faults into the scripted calls", func() {
+			rule := &rdstest.FaultRule{SQL: regexp.MustCompile(`^SELECT 1`), Calls: []int{2, 3}, Err: rdstest.DatabaseResuming()}
+			faults.AddRule(rule)
+
+			So(execute("SELECT 1"), ShouldBeNil)
+			So(execute("SELECT 2"), ShouldBeNil)
+			err := execute("SELECT 1")
+			var resuming *types.DatabaseResumingException
+			So(errors.As(err, &resuming), ShouldBeTrue)
+			So(execute("SELECT 1"), ShouldNotBeNil)
+			So(execute("SELECT 1"), ShouldBeNil)
+			So(rule.Injected(), ShouldEqual, 2)
+		})
+
+		Convey("Injects faults at most the given times", func() {
+			rule := &rdstest.FaultRule{Operation: rdstest.OperationExecuteStatement, Times: 1, Drop: true}
+			faults.AddRule(rule)
+
+			So(errors.Is(execute("SELECT 1"), rdstest.ErrInjected), ShouldBeTrue)
+			So(execute("SELECT 1"), ShouldBeNil)
+			So(rule.Injected(), ShouldEqual, 1)
+		})
+
+		Convey("Injects faults with the given probability", func() {
+			injected := func(seed uint64) int {
+				faults := rdstest.NewFaultInjector(fake)
+				faults.Seed(seed)
+				rule := &rdstest.FaultRule{Probability: 0.5, Err: rdstest.Throttling()}
+				faults.AddRule(rule)
+				for i := 0; i < 100; i++ {
+					_, _ = faults.ExecuteStatement(ctx, &rdsdata.ExecuteStatementInput{
+						ResourceArn: aws.String("resourceARN"),
+						SecretArn:   aws.String("secretARN"),
+						Sql:         aws.String("SELECT 1"),
+					})
+				}
+				return rule.Injected()
+			}
+			So(injected(42), ShouldBeBetween, 25, 75)
+			So(injected(42), ShouldEqual, injected(42))
+		})
+	})
+}
@@ -0,0 +1,284 @@
+package rdstest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/service/rdsdata"
+	"github.com/aws/smithy-go"
+	"github.com/krotscheck/go-rds-driver"
+	"github.com/krotscheck/go-rds-driver/internal/dataapi"
+)
+
+var _ rds.AWSClientInterface = (*Recorder)(nil) // explicit compile time type check
+
+// Field of a record or parameter, in the JSON of the Data API's REST protocol.
+type Field = dataapi.Field
+
+// Parameter of a statement, in the JSON of the Data API's REST protocol.
+type Parameter = dataapi.Parameter
+
+// ColumnMetadata of a result, in the JSON of the Data API's REST protocol.
+type ColumnMetadata = dataapi.ColumnMetadata
+
+// Cassette of the requests made to the Data API and their responses, as saved to golden files.
+type Cassette struct {
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// Interaction with the Data API, of which either the response or the error is set.
+type Interaction struct {
+	Operation string    `json:"operation"`
+	Request   Request   `json:"request"`
+	Response  *Response `json:"response,omitempty"`
+	Error     *Error    `json:"error,omitempty"`
+}
+
+// Request to the Data API, of any operation.
+type Request struct {
+	ResourceArn           string      `json:"resourceArn,omitempty"`
+	SecretArn             string      `json:"secretArn,omitempty"`
+	SQL                   string      `json:"sql,omitempty"`
+	Database              string      `json:"database,omitempty"`
+	Schema                string      `json:"schema,omitempty"`
+	Parameters            []Parameter `json:"parameters,omitempty"`
+	TransactionID         string      `json:"transactionId,omitempty"`
+	IncludeResultMetadata bool        `json:"includeResultMetadata,omitempty"`
+	ContinueAfterTimeout  bool        `json:"continueAfterTimeout,omitempty"`
+	FormatRecordsAs       string      `json:"formatRecordsAs,omitempty"`
+}
+
+// Response of the Data API, of any operation.
+type Response struct {
+	ColumnMetadata         []ColumnMetadata `json:"columnMetadata,omitempty"`
+	FormattedRecords       *string          `json:"formattedRecords,omitempty"`
+	GeneratedFields        []Field          `json:"generatedFields,omitempty"`
+	NumberOfRecordsUpdated int64            `json:"numberOfRecordsUpdated,omitempty"`
+	Records                [][]Field        `json:"records,omitempty"`
+	TransactionID          string           `json:"transactionId,omitempty"`
+	TransactionStatus      string           `json:"transactionStatus,omitempty"`
+}
+
+// Error returned by the Data API. Errors without a code didn't come from the service, such as those of
+// the network.
+type Error struct {
+	Code       string `json:"code,omitempty"`
+	Message    string `json:"message"`
+	StatusCode int    `json:"statusCode,omitempty"`
+	RequestID  string `json:"requestId,omitempty"`
+}
+
+// LoadCassette from a golden file.
+func LoadCassette(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	cassette := &Cassette{}
+	if err := json.Unmarshal(data, cassette); err != nil {
+		return nil, err
+	}
+	return cassette, nil
+}
+
+// Save the cassette as a golden file, creating its directory if needed.
+func (c *Cassette) Save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// RecordOption configures the Recorder.
+type RecordOption func(*Recorder)
+
+// WithARNRedaction replaces the resource and secret ARNs of recorded requests, and any mention of them
+// in errors, by the result of redact. A nil function records them as they are. Defaults to RedactARN.
+func WithARNRedaction(redact func(arn string) string) RecordOption {
+	return func(r *Recorder) {
+		r.redact = redact
+	}
+}
+
+// RedactARN replaces the account ID of the ARN with zeros.
+func RedactARN(arn string) string {
+	parts := strings.SplitN(arn, ":", 6)
+	if len(parts) < 6 || parts[0] != "arn" || parts[4] == "" {
+		return arn
+	}
+	parts[4] = "000000000000"
+	return strings.Join(parts, ":")
+}
+
+// NewRecorder of the requests made to the client and its responses, such as to capture those of a real
+// cluster for replay in tests which don't have one.
+func NewRecorder(client rds.AWSClientInterface, opts ...RecordOption) *Recorder {
+	r := &Recorder{client: client, redact: RedactARN, cassette: &Cassette{Interactions: []*Interaction{}}}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Recorder wrapping a client of the Data API, recording its interactions in the order they completed.
+type Recorder struct {
+	client rds.AWSClientInterface
+	redact func(arn string) string
+
+	mu       sync.Mutex
+	cassette *Cassette
+}
+
+// Cassette of the interactions recorded so far.
+func (r *Recorder) Cassette() *Cassette {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return &Cassette{Interactions: append([]*Interaction{}, r.cassette.Interactions...)}
+}
+
+// Save the interactions recorded so far as a golden file.
+func (r *Recorder) Save(path string) error {
+	return r.Cassette().Save(path)
+}
+
+// ExecuteStatement with the wrapped client, recording its result.
+func (r *Recorder) ExecuteStatement(ctx context.Context, input *rdsdata.ExecuteStatementInput, optFns ...func(*rdsdata.Options)) (*rdsdata.ExecuteStatementOutput, error) {
+	output, err := r.client.ExecuteStatement(ctx, input, optFns...)
+	interaction := &Interaction{
+		Operation: "ExecuteStatement",
+		Request: Request{
+			ResourceArn:           aws.ToString(input.ResourceArn),
+			SecretArn:             aws.ToString(input.SecretArn),
+			SQL:                   aws.ToString(input.Sql),
+			Database:              aws.ToString(input.Database),
+			Schema:                aws.ToString(input.Schema),
+			Parameters:            dataapi.FromParameters(input.Parameters),
+			TransactionID:         aws.ToString(input.TransactionId),
+			IncludeResultMetadata: input.IncludeResultMetadata,
+			ContinueAfterTimeout:  input.ContinueAfterTimeout,
+			FormatRecordsAs:       string(input.FormatRecordsAs),
+		},
+	}
+	if output != nil {
+		interaction.Response = &Response{
+			ColumnMetadata:         dataapi.FromColumnMetadata(output.ColumnMetadata),
+			FormattedRecords:       output.FormattedRecords,
+			GeneratedFields:        dataapi.FromFields(output.GeneratedFields),
+			NumberOfRecordsUpdated: output.NumberOfRecordsUpdated,
+		}
+		for _, record := range output.Records {
+			interaction.Response.Records = append(interaction.Response.Records, dataapi.FromFields(record))
+		}
+	}
+	r.record(interaction, err)
+	return output, err
+}
+
+// BeginTransaction with the wrapped client, recording its result.
+func (r *Recorder) BeginTransaction(ctx context.Context, input *rdsdata.BeginTransactionInput, optFns ...func(*rdsdata.Options)) (*rdsdata.BeginTransactionOutput, error) {
+	output, err := r.client.BeginTransaction(ctx, input, optFns...)
+	interaction := &Interaction{
+		Operation: "BeginTransaction",
+		Request: Request{
+			ResourceArn: aws.ToString(input.ResourceArn),
+			SecretArn:   aws.ToString(input.SecretArn),
+			Database:    aws.ToString(input.Database),
+			Schema:      aws.ToString(input.Schema),
+		},
+	}
+	if output != nil {
+		interaction.Response = &Response{TransactionID: aws.ToString(output.TransactionId)}
+	}
+	r.record(interaction, err)
+	return output, err
+}
+
+// CommitTransaction with the wrapped client, recording its result.
+func (r *Recorder) CommitTransaction(ctx context.Context, input *rdsdata.CommitTransactionInput, optFns ...func(*rdsdata.Options)) (*rdsdata.CommitTransactionOutput, error) {
+	output, err := r.client.CommitTransaction(ctx, input, optFns...)
+	interaction := &Interaction{
+		Operation: "CommitTransaction",
+		Request: Request{
+			ResourceArn:   aws.ToString(input.ResourceArn),
+			SecretArn:     aws.ToString(input.SecretArn),
+			TransactionID: aws.ToString(input.TransactionId),
+		},
+	}
+	if output != nil {
+		interaction.Response = &Response{TransactionStatus: aws.ToString(output.TransactionStatus)}
+	}
+	r.record(interaction, err)
+	return output, err
+}
+
+// RollbackTransaction with the wrapped client, recording its result.
+func (r *Recorder) RollbackTransaction(ctx context.Context, input *rdsdata.RollbackTransactionInput, optFns ...func(*rdsdata.Options)) (*rdsdata.RollbackTransactionOutput, error) {
+	output, err := r.client.RollbackTransaction(ctx, input, optFns...)
+	interaction := &Interaction{
+		Operation: "RollbackTransaction",
+		Request: Request{
+			ResourceArn:   aws.ToString(input.ResourceArn),
+			SecretArn:     aws.ToString(input.SecretArn),
+			TransactionID: aws.ToString(input.TransactionId),
+		},
+	}
+	if output != nil {
+		interaction.Response = &Response{TransactionStatus: aws.ToString(output.TransactionStatus)}
+	}
+	r.record(interaction, err)
+	return output, err
+}
+
+// record the interaction with its error, if any, redacting its ARNs.
+func (r *Recorder) record(interaction *Interaction, err error) {
+	if err != nil {
+		interaction.Response = nil
+		interaction.Error = newError(err)
+	}
+	if r.redact != nil {
+		for _, arn := range []*string{&interaction.Request.ResourceArn, &interaction.Request.SecretArn} {
+			if *arn == "" {
+				continue
+			}
+			redacted := r.redact(*arn)
+			if interaction.Error != nil {
+				interaction.Error.Message = strings.ReplaceAll(interaction.Error.Message, *arn, redacted)
+			}
+			*arn = redacted
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+}
+
+// newError recording the exception, status and request ID of an error returned by the SDK.
+func newError(err error) *Error {
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) {
+		var opErr *smithy.OperationError
+		if errors.As(err, &opErr) {
+			err = opErr.Err
+		}
+		return &Error{Message: err.Error()}
+	}
+	recorded := &Error{Code: apiErr.ErrorCode(), Message: apiErr.ErrorMessage()}
+	var respErr *awshttp.ResponseError
+	if errors.As(err, &respErr) {
+		recorded.StatusCode = respErr.HTTPStatusCode()
+		recorded.RequestID = respErr.ServiceRequestID()
+	}
+	return recorded
+}
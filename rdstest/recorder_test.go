@@ -0,0 +1,134 @@
+package rdstest_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rdsdata"
+	"github.com/aws/aws-sdk-go-v2/service/rdsdata/types"
+	"github.com/krotscheck/go-rds-driver"
+	"github.com/krotscheck/go-rds-driver/rdstest"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const (
+	recordedResourceARN = "arn:aws:rds:us-west-2:123456789012:cluster:mysql"
+	recordedSecretARN   = "arn:aws:secretsmanager:us-west-2:123456789012:secret:aurora_password"
+)
+
+// exercise the driver, returning the name, balance and avatar selected, and the error of a duplicate insert.
+func exercise(ctx context.Context, client rds.AWSClientInterface) (string, float64, []byte, error) {
+	conf := rds.NewConfig(recordedResourceARN, recordedSecretARN, "database", "us-west-2")
+	db := sql.OpenDB(rds.NewConnector(rds.NewDriver(), client, conf))
+	defer db.Close()
+
+	_, err := db.ExecContext(ctx, "CREATE TABLE accounts (id INTEGER PRIMARY KEY AUTOINCREMENT, name VARCHAR(255) NOT NULL UNIQUE, balance DOUBLE, avatar BLOB)")
+	So(err, ShouldBeNil)
+	tx, err := db.BeginTx(ctx, nil)
+	So(err, ShouldBeNil)
+	_, err = tx.ExecContext(ctx, "INSERT INTO accounts (name, balance, avatar) VALUES (?, ?, ?)", "alice", 10.5, []byte{0x01, 0x02})
+	So(err, ShouldBeNil)
+	So(tx.Commit(), ShouldBeNil)
+
+	var name string
+	var balance float64
+	var avatar []byte
+	So(db.QueryRowContext(ctx, "SELECT name, balance, avatar FROM accounts WHERE id = ?", 1).Scan(&name, &balance, &avatar), ShouldBeNil)
+	_, err = db.ExecContext(ctx, "INSERT INTO accounts (name) VALUES (?)", "alice")
+	return name, balance, avatar, err
+}
+
+func Test_Recorder(t *testing.T) {
+	ctx := context.Background()
+
+	Convey("Recorder", t, func() {
+		fake := rdstest.NewFake()
+		defer fake.Close()
+		recorder := rdstest.NewRecorder(fake)
+		name, balance, avatar, recordedErr := exercise(ctx, recorder)
+		So(name, ShouldEqual, "alice")
+		So(recordedErr, ShouldNotBeNil)
+
+		path := filepath.Join(t.TempDir(), "testdata", "accounts.json")
+		So(recorder.Save(path), ShouldBeNil)
+
+		Convey("Redacts ARNs", func() {
+			data, err := os.ReadFile(path)
+			So(err, ShouldBeNil)
+			So(string(data), ShouldNotContainSubstring, "123456789012")
+			So(string(data), ShouldContainSubstring, "arn:aws:rds:us-west-2:000000000000:cluster:mysql")
+
+			recorder := rdstest.NewRecorder(fake, rdstest.WithARNRedaction(nil))
+			_, err = recorder.ExecuteStatement(ctx, &rdsdata.ExecuteStatementInput{
+				ResourceArn: aws.String(recordedResourceARN),
+				SecretArn:   aws.String(recordedSecretARN),
+				Sql:         aws.String("SELECT 1"),
+			})
+			So(err, ShouldBeNil)
+			So(recorder.Cassette().Interactions[0].Request.ResourceArn, ShouldEqual, recordedResourceARN)
+		})
+
+		Convey("Replays the recording", func() {
+			cassette, err := rdstest.LoadCassette(path)
+			So(err, ShouldBeNil)
+			replayer := rdstest.NewReplayer(cassette)
+
+			replayedName, replayedBalance, replayedAvatar, replayedErr := exercise(ctx, replayer)
+			So(replayedName, ShouldEqual, name)
+			So(replayedBalance, ShouldEqual, balance)
+			So(replayedAvatar, ShouldResemble, avatar)
+			So(replayer.Remaining(), ShouldEqual, 0)
+
+			var recordedStmtErr, replayedStmtErr *rds.StatementError
+			So(errors.As(recordedErr, &recordedStmtErr), ShouldBeTrue)
+			So(errors.As(replayedErr, &replayedStmtErr), ShouldBeTrue)
+			So(replayedStmtErr.RequestID, ShouldEqual, recordedStmtErr.RequestID)
+			var badRequest *types.BadRequestException
+			So(errors.As(replayedErr, &badRequest), ShouldBeTrue)
+			So(badRequest.ErrorMessage(), ShouldContainSubstring, "UNIQUE constraint failed")
+		})
+
+		Convey("Rejects requests which weren't recorded", func() {
+			replayer := rdstest.NewReplayer(recorder.Cassette())
+			_, err := replayer.ExecuteStatement(ctx, &rdsdata.ExecuteStatementInput{Sql: aws.String("SELECT 2")})
+			So(errors.Is(err, rdstest.ErrNoInteraction), ShouldBeTrue)
+		})
+
+		Convey("Matches requests with the configured matcher", func() {
+			replayer := rdstest.NewReplayer(recorder.Cassette(), rdstest.WithMatcher(rdstest.MatchSQL))
+			output, err := replayer.ExecuteStatement(ctx, &rdsdata.ExecuteStatementInput{
+				Sql: aws.String("SELECT  name, balance, avatar\nFROM accounts WHERE id = :1"),
+				Parameters: []types.SqlParameter{
+					{Name: aws.String("1"), Value: &types.FieldMemberLongValue{Value: 2}},
+				},
+			})
+			So(err, ShouldBeNil)
+			So(output.Records[0][0], ShouldResemble, &types.FieldMemberStringValue{Value: "alice"})
+		})
+	})
+
+	Convey("Replayer", t, func() {
+		Convey("Decodes array values", func() {
+			path := filepath.Join(t.TempDir(), "arrays.json")
+			So(os.WriteFile(path, []byte(`{"interactions": [{
+				"operation": "ExecuteStatement",
+				"request": {"sql": "SELECT tags FROM posts"},
+				"response": {"records": [[{"arrayValue": {"longValues": [1, 2]}}, {"isNull": true}]]}
+			}]}`), 0o644), ShouldBeNil)
+			cassette, err := rdstest.LoadCassette(path)
+			So(err, ShouldBeNil)
+
+			output, err := rdstest.NewReplayer(cassette).ExecuteStatement(ctx, &rdsdata.ExecuteStatementInput{Sql: aws.String("SELECT tags FROM posts")})
+			So(err, ShouldBeNil)
+			So(output.Records[0], ShouldResemble, []types.Field{
+				&types.FieldMemberArrayValue{Value: &types.ArrayValueMemberLongValues{Value: []int64{1, 2}}},
+				&types.FieldMemberIsNull{Value: true},
+			})
+		})
+	})
+}
@@ -0,0 +1,226 @@
+package rdstest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rdsdata"
+	"github.com/aws/aws-sdk-go-v2/service/rdsdata/types"
+	"github.com/aws/smithy-go"
+	"github.com/krotscheck/go-rds-driver"
+	"github.com/krotscheck/go-rds-driver/internal/dataapi"
+)
+
+var _ rds.AWSClientInterface = (*Replayer)(nil) // explicit compile time type check
+
+// ErrNoInteraction is returned by the Replayer for requests which match none of the remaining interactions.
+var ErrNoInteraction = fmt.Errorf("no recorded interaction matches the request")
+
+// Matcher reports whether a recorded request matches the one being replayed. Requests are only ever
+// matched against recordings of the same operation.
+type Matcher func(recorded, request *Request) bool
+
+// MatchSQL of the requests, ignoring differences in whitespace.
+func MatchSQL(recorded, request *Request) bool {
+	return strings.Join(strings.Fields(recorded.SQL), " ") == strings.Join(strings.Fields(request.SQL), " ")
+}
+
+// MatchParameters of the requests, including their names and type hints.
+func MatchParameters(recorded, request *Request) bool {
+	if len(recorded.Parameters) == 0 && len(request.Parameters) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(recorded.Parameters, request.Parameters)
+}
+
+// MatchTransaction of the requests. Transactions replayed from a recording have its IDs.
+func MatchTransaction(recorded, request *Request) bool {
+	return recorded.TransactionID == request.TransactionID
+}
+
+// MatchAll of the matchers.
+func MatchAll(matchers ...Matcher) Matcher {
+	return func(recorded, request *Request) bool {
+		for _, match := range matchers {
+			if !match(recorded, request) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// ReplayOption configures the Replayer.
+type ReplayOption func(*Replayer)
+
+// WithMatcher of the requests. Defaults to matching their SQL and parameters.
+func WithMatcher(matcher Matcher) ReplayOption {
+	return func(r *Replayer) {
+		r.match = matcher
+	}
+}
+
+// NewReplayer of the interactions in the cassette.
+func NewReplayer(cassette *Cassette, opts ...ReplayOption) *Replayer {
+	r := &Replayer{
+		cassette: cassette,
+		match:    MatchAll(MatchSQL, MatchParameters),
+		replayed: make([]bool, len(cassette.Interactions)),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Replayer of recorded interactions with the Data API. Every request is answered by the first interaction
+// of the same operation which matches it and hasn't been replayed yet, so that repeated requests are
+// answered in the order they were recorded.
+type Replayer struct {
+	cassette *Cassette
+	match    Matcher
+
+	mu       sync.Mutex
+	replayed []bool
+}
+
+// Remaining interactions which haven't been replayed.
+func (r *Replayer) Remaining() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	remaining := 0
+	for _, replayed := range r.replayed {
+		if !replayed {
+			remaining++
+		}
+	}
+	return remaining
+}
+
+// ExecuteStatement returning the recorded response.
+func (r *Replayer) ExecuteStatement(_ context.Context, input *rdsdata.ExecuteStatementInput, _ ...func(*rdsdata.Options)) (*rdsdata.ExecuteStatementOutput, error) {
+	const operation = "ExecuteStatement"
+	response, err := r.replay(operation, &Request{
+		ResourceArn:           aws.ToString(input.ResourceArn),
+		SecretArn:             aws.ToString(input.SecretArn),
+		SQL:                   aws.ToString(input.Sql),
+		Database:              aws.ToString(input.Database),
+		Schema:                aws.ToString(input.Schema),
+		Parameters:            dataapi.FromParameters(input.Parameters),
+		TransactionID:         aws.ToString(input.TransactionId),
+		IncludeResultMetadata: input.IncludeResultMetadata,
+		ContinueAfterTimeout:  input.ContinueAfterTimeout,
+		FormatRecordsAs:       string(input.FormatRecordsAs),
+	})
+	if err != nil {
+		return nil, err
+	}
+	output := &rdsdata.ExecuteStatementOutput{
+		ColumnMetadata:         dataapi.ToColumnMetadata(response.ColumnMetadata),
+		FormattedRecords:       response.FormattedRecords,
+		GeneratedFields:        dataapi.ToFields(response.GeneratedFields),
+		NumberOfRecordsUpdated: response.NumberOfRecordsUpdated,
+	}
+	for _, record := range response.Records {
+		output.Records = append(output.Records, dataapi.ToFields(record))
+	}
+	return output, nil
+}
+
+// BeginTransaction returning the recorded response.
+func (r *Replayer) BeginTransaction(_ context.Context, input *rdsdata.BeginTransactionInput, _ ...func(*rdsdata.Options)) (*rdsdata.BeginTransactionOutput, error) {
+	response, err := r.replay("BeginTransaction", &Request{
+		ResourceArn: aws.ToString(input.ResourceArn),
+		SecretArn:   aws.ToString(input.SecretArn),
+		Database:    aws.ToString(input.Database),
+		Schema:      aws.ToString(input.Schema),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &rdsdata.BeginTransactionOutput{TransactionId: aws.String(response.TransactionID)}, nil
+}
+
+// CommitTransaction returning the recorded response.
+func (r *Replayer) CommitTransaction(_ context.Context, input *rdsdata.CommitTransactionInput, _ ...func(*rdsdata.Options)) (*rdsdata.CommitTransactionOutput, error) {
+	response, err := r.replay("CommitTransaction", &Request{
+		ResourceArn:   aws.ToString(input.ResourceArn),
+		SecretArn:     aws.ToString(input.SecretArn),
+		TransactionID: aws.ToString(input.TransactionId),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &rdsdata.CommitTransactionOutput{TransactionStatus: aws.String(response.TransactionStatus)}, nil
+}
+
+// RollbackTransaction returning the recorded response.
+func (r *Replayer) RollbackTransaction(_ context.Context, input *rdsdata.RollbackTransactionInput, _ ...func(*rdsdata.Options)) (*rdsdata.RollbackTransactionOutput, error) {
+	response, err := r.replay("RollbackTransaction", &Request{
+		ResourceArn:   aws.ToString(input.ResourceArn),
+		SecretArn:     aws.ToString(input.SecretArn),
+		TransactionID: aws.ToString(input.TransactionId),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &rdsdata.RollbackTransactionOutput{TransactionStatus: aws.String(response.TransactionStatus)}, nil
+}
+
+// replay the first remaining interaction of the operation which matches the request.
+func (r *Replayer) replay(operation string, request *Request) (*Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, interaction := range r.cassette.Interactions {
+		if r.replayed[i] || interaction.Operation != operation || !r.match(&interaction.Request, request) {
+			continue
+		}
+		r.replayed[i] = true
+		if interaction.Error != nil {
+			return nil, interaction.Error.err(operation)
+		}
+		if interaction.Response == nil {
+			return &Response{}, nil
+		}
+		return interaction.Response, nil
+	}
+	return nil, fmt.Errorf("%w: %s %q", ErrNoInteraction, operation, request.SQL)
+}
+
+// exceptions of the Data API, by their code.
+var exceptions = map[string]func(message *string) error{
+	"AccessDeniedException":           func(m *string) error { return &types.AccessDeniedException{Message: m} },
+	"BadRequestException":             func(m *string) error { return &types.BadRequestException{Message: m} },
+	"DatabaseErrorException":          func(m *string) error { return &types.DatabaseErrorException{Message: m} },
+	"DatabaseNotFoundException":       func(m *string) error { return &types.DatabaseNotFoundException{Message: m} },
+	"DatabaseResumingException":       func(m *string) error { return &types.DatabaseResumingException{Message: m} },
+	"DatabaseUnavailableException":    func(m *string) error { return &types.DatabaseUnavailableException{Message: m} },
+	"ForbiddenException":              func(m *string) error { return &types.ForbiddenException{Message: m} },
+	"HttpEndpointNotEnabledException": func(m *string) error { return &types.HttpEndpointNotEnabledException{Message: m} },
+	"InternalServerErrorException":    func(m *string) error { return &types.InternalServerErrorException{Message: m} },
+	"InvalidResourceStateException":   func(m *string) error { return &types.InvalidResourceStateException{Message: m} },
+	"InvalidSecretException":          func(m *string) error { return &types.InvalidSecretException{Message: m} },
+	"NotFoundException":               func(m *string) error { return &types.NotFoundException{Message: m} },
+	"SecretsErrorException":           func(m *string) error { return &types.SecretsErrorException{Message: m} },
+	"ServiceUnavailableError":         func(m *string) error { return &types.ServiceUnavailableError{Message: m} },
+	"StatementTimeoutException":       func(m *string) error { return &types.StatementTimeoutException{Message: m} },
+	"TransactionNotFoundException":    func(m *string) error { return &types.TransactionNotFoundException{Message: m} },
+	"UnsupportedResultException":      func(m *string) error { return &types.UnsupportedResultException{Message: m} },
+}
+
+// err recreating the recorded error, as the SDK returned it.
+func (e *Error) err(operation string) error {
+	if e.Code == "" {
+		return &smithy.OperationError{ServiceID: rdsdata.ServiceID, OperationName: operation, Err: errors.New(e.Message)}
+	}
+	var exception error = &smithy.GenericAPIError{Code: e.Code, Message: e.Message}
+	if newException, ok := exceptions[e.Code]; ok {
+		exception = newException(aws.String(e.Message))
+	}
+	return responseError(operation, exception, e.StatusCode, e.RequestID)
+}
@@ -0,0 +1,147 @@
+package rds_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/rdsdata/types"
+	"github.com/krotscheck/go-rds-driver"
+	"github.com/krotscheck/go-rds-driver/rdstest"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func Test_Resilience(t *testing.T) {
+	ctx := context.Background()
+	policy := &rds.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+
+	Convey("Resilience", t, func() {
+		fake := rdstest.NewFake()
+		defer fake.Close()
+		_, err := fake.DB().ExecContext(ctx, "CREATE TABLE accounts (id INTEGER PRIMARY KEY, balance INTEGER NOT NULL)")
+		So(err, ShouldBeNil)
+		_, err = fake.DB().ExecContext(ctx, "INSERT INTO accounts (id, balance) VALUES (1, 10)")
+		So(err, ShouldBeNil)
+
+		faults := rdstest.NewFaultInjector(fake)
+		conf := rds.NewConfig("resourceARN", "secretARN", "database", "region")
+		db := sql.OpenDB(rds.NewConnector(rds.NewDriver(), faults, conf))
+		defer db.Close()
+		// A single connection is reused, so that each test observes how its session is reset.
+		db.SetMaxOpenConns(1)
+
+		balance := func() int {
+			var balance int
+			So(fake.DB().QueryRowContext(ctx, "SELECT balance FROM accounts WHERE id = 1").Scan(&balance), ShouldBeNil)
+			return balance
+		}
+		deposit := func(tx *sql.Tx) error {
+			_, err := tx.ExecContext(ctx, "UPDATE accounts SET balance = balance + 1 WHERE id = 1")
+			return err
+		}
+
+		Convey("Wakes up a resuming cluster", func() {
+			rule := &rdstest.FaultRule{SQL: regexp.MustCompile(`wakeup`), Calls: []int{1}, Err: rdstest.DatabaseResuming()}
+			faults.AddRule(rule)
+
+			So(db.PingContext(ctx), ShouldBeNil)
+			So(rule.Injected(), ShouldEqual, 1)
+		})
+
+		Convey("Retries throttled transactions", func() {
+			rule := &rdstest.FaultRule{Operation: rdstest.OperationBeginTransaction, Calls: []int{1}, Err: rdstest.Throttling()}
+			faults.AddRule(rule)
+
+			So(policy.RunInTx(ctx, db, nil, deposit), ShouldBeNil)
+			So(rule.Injected(), ShouldEqual, 1)
+			So(balance(), ShouldEqual, 11)
+		})
+
+		Convey("Retries transactions which failed midway", func() {
+			rule := &rdstest.FaultRule{SQL: regexp.MustCompile(`^UPDATE`), Calls: []int{1}, Err: rdstest.DatabaseUnavailable()}
+			faults.AddRule(rule)
+
+			So(policy.RunInTx(ctx, db, nil, deposit), ShouldBeNil)
+			So(rule.Injected(), ShouldEqual, 1)
+			So(balance(), ShouldEqual, 11)
+			So(fake.OpenTransactions(), ShouldEqual, 0)
+		})
+
+		Convey("Gives up once out of attempts", func() {
+			rule := &rdstest.FaultRule{SQL: regexp.MustCompile(`^UPDATE`), Err: rdstest.DatabaseResuming()}
+			faults.AddRule(rule)
+
+			err := policy.RunInTx(ctx, db, nil, deposit)
+			var resuming *types.DatabaseResumingException
+			So(errors.As(err, &resuming), ShouldBeTrue)
+			So(rule.Injected(), ShouldEqual, 3)
+			So(balance(), ShouldEqual, 10)
+			So(fake.OpenTransactions(), ShouldEqual, 0)
+		})
+
+		Convey("Abandons slow statements", func() {
+			faults.AddRule(&rdstest.FaultRule{SQL: regexp.MustCompile(`^SELECT balance`), Latency: time.Second})
+			So(db.PingContext(ctx), ShouldBeNil)
+
+			timeout, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+			defer cancel()
+			var balance int
+			err := db.QueryRowContext(timeout, "SELECT balance FROM accounts").Scan(&balance)
+			So(errors.Is(err, context.DeadlineExceeded), ShouldBeTrue)
+		})
+
+		Convey("Rolls back a transaction whose commit was dropped", func() {
+			rule := &rdstest.FaultRule{Operation: rdstest.OperationCommitTransaction, Calls: []int{1}, Drop: true}
+			faults.AddRule(rule)
+
+			tx, err := db.BeginTx(ctx, nil)
+			So(err, ShouldBeNil)
+			So(deposit(tx), ShouldBeNil)
+			So(errors.Is(tx.Commit(), rdstest.ErrInjected), ShouldBeTrue)
+			So(fake.OpenTransactions(), ShouldEqual, 1)
+
+			// Resetting the session of the connection rolls back the transaction it was left with.
+			So(db.PingContext(ctx), ShouldBeNil)
+			So(fake.OpenTransactions(), ShouldEqual, 0)
+			So(balance(), ShouldEqual, 10)
+		})
+
+		Convey("Discards the connection of a commit whose response was lost", func() {
+			rule := &rdstest.FaultRule{Operation: rdstest.OperationCommitTransaction, Calls: []int{1}, LoseResponse: true}
+			faults.AddRule(rule)
+
+			tx, err := db.BeginTx(ctx, nil)
+			So(err, ShouldBeNil)
+			So(deposit(tx), ShouldBeNil)
+			So(errors.Is(tx.Commit(), rdstest.ErrInjected), ShouldBeTrue)
+			So(balance(), ShouldEqual, 11)
+
+			// The transaction was committed, so it can no longer be rolled back.
+			var count int
+			So(db.QueryRowContext(ctx, "SELECT COUNT(*) FROM accounts").Scan(&count), ShouldBeNil)
+			So(count, ShouldEqual, 1)
+			So(fake.OpenTransactions(), ShouldEqual, 0)
+		})
+
+		Convey("Discards the connection of a transaction which couldn't be rolled back", func() {
+			faults.AddRule(&rdstest.FaultRule{Operation: rdstest.OperationCommitTransaction, Calls: []int{1}, Drop: true})
+			rollback := &rdstest.FaultRule{Operation: rdstest.OperationRollbackTransaction, Calls: []int{1}, Err: rdstest.DatabaseUnavailable()}
+			faults.AddRule(rollback)
+
+			tx, err := db.BeginTx(ctx, nil)
+			So(err, ShouldBeNil)
+			So(deposit(tx), ShouldBeNil)
+			So(tx.Commit(), ShouldNotBeNil)
+
+			// The statement mustn't be executed within the transaction left behind.
+			_, err = db.ExecContext(ctx, "UPDATE accounts SET balance = 0 WHERE id = 1")
+			So(err, ShouldBeNil)
+			So(rollback.Injected(), ShouldEqual, 1)
+			So(balance(), ShouldEqual, 0)
+			So(fake.OpenTransactions(), ShouldEqual, 0)
+		})
+	})
+}
@@ -10,6 +10,10 @@ var _ driver.Result = (*Result)(nil) // explicit compile time type check
 
 // NewResult for the executed statement
 func NewResult(results []*rdsdata.ExecuteStatementOutput) driver.Result {
+	return newResult(results)
+}
+
+func newResult(results []*rdsdata.ExecuteStatementOutput) *Result {
 	// Calculate the total number of affected rows
 	var rowsAffected int64 = 0
 	var lastInsertID int64 = 0
@@ -41,13 +45,14 @@ func NewResult(results []*rdsdata.ExecuteStatementOutput) driver.Result {
 
 // Result from a queries
 type Result struct {
-	rowsAffected int64
-	lastInsertID int64
+	rowsAffected    int64
+	lastInsertID    int64
+	lastInsertIDErr error
 }
 
 // LastInsertId from the executed statements.
 func (r *Result) LastInsertId() (int64, error) {
-	return r.lastInsertID, nil
+	return r.lastInsertID, r.lastInsertIDErr
 }
 
 // RowsAffected count
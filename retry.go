@@ -0,0 +1,138 @@
+package rds
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/rdsdata/types"
+	"github.com/aws/smithy-go"
+)
+
+// DefaultRetryPolicy used by RunInTx.
+var DefaultRetryPolicy = &RetryPolicy{
+	MaxAttempts:    5,
+	InitialBackoff: 100 * time.Millisecond,
+	MaxBackoff:     5 * time.Second,
+}
+
+// RetryPolicy describes how RunInTx retries transactions which failed with a retryable error.
+type RetryPolicy struct {
+	// MaxAttempts at running the transaction, including the first.
+	MaxAttempts int
+	// InitialBackoff before the first retry, doubling with each subsequent retry.
+	InitialBackoff time.Duration
+	// MaxBackoff between any two attempts.
+	MaxBackoff time.Duration
+	// Retryable decides whether an error is retryable. Defaults to IsRetryable.
+	Retryable func(err error) bool
+	// OnAttempt is called after every attempt with its result, such as to record metrics.
+	OnAttempt func(attempt int, err error)
+	// OnRetry is called before waiting to retry a failed attempt.
+	OnRetry func(attempt int, err error, backoff time.Duration)
+}
+
+// IsRetryable reports whether the error, once the whole transaction is retried, might succeed. This
+// includes serialization failures, deadlocks, lock timeouts and expired transactions as classified
+// by the dialect, as well as transient Data API errors such as a resuming cluster or throttling.
+func IsRetryable(err error) bool {
+	switch {
+	case errors.Is(err, ErrSerializationFailure),
+		errors.Is(err, ErrDeadlock),
+		errors.Is(err, ErrLockTimeout),
+		errors.Is(err, ErrTransactionExpired):
+		return true
+	}
+
+	var resuming *types.DatabaseResumingException
+	var unavailable *types.DatabaseUnavailableException
+	if errors.As(err, &resuming) || errors.As(err, &unavailable) {
+		return true
+	}
+
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.ErrorCode() {
+		case "ThrottlingException", "TooManyRequestsException":
+			return true
+		}
+	}
+	return false
+}
+
+// RunInTx runs the callback within a transaction, which is committed if the callback succeeds and
+// rolled back otherwise. The whole transaction is retried according to the DefaultRetryPolicy.
+func RunInTx(ctx context.Context, db *sql.DB, opts *sql.TxOptions, fn func(*sql.Tx) error) error {
+	return DefaultRetryPolicy.RunInTx(ctx, db, opts, fn)
+}
+
+// RunInTx runs the callback within a transaction, which is committed if the callback succeeds and
+// rolled back otherwise. The whole transaction is retried according to this policy.
+func (p *RetryPolicy) RunInTx(ctx context.Context, db *sql.DB, opts *sql.TxOptions, fn func(*sql.Tx) error) (err error) {
+	retryable := p.Retryable
+	if retryable == nil {
+		retryable = IsRetryable
+	}
+	attempts := p.MaxAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	for attempt := 1; ; attempt++ {
+		err = runInTx(ctx, db, opts, fn)
+		if p.OnAttempt != nil {
+			p.OnAttempt(attempt, err)
+		}
+		if err == nil || !retryable(err) {
+			return err
+		}
+		if attempt >= attempts {
+			return fmt.Errorf("after %d attempts, last error: %w", attempts, err)
+		}
+
+		backoff := p.backoff(attempt)
+		if p.OnRetry != nil {
+			p.OnRetry(attempt, err, backoff)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+	}
+}
+
+// backoff before the retry following the passed attempt, with jitter.
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	backoff := p.InitialBackoff << (attempt - 1)
+	if backoff <= 0 || (p.MaxBackoff > 0 && backoff > p.MaxBackoff) {
+		backoff = p.MaxBackoff
+	}
+	if backoff <= 0 {
+		return 0
+	}
+	half := backoff / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+func runInTx(ctx context.Context, db *sql.DB, opts *sql.TxOptions, fn func(*sql.Tx) error) (err error) {
+	tx, err := db.BeginTx(ctx, opts)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err = fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
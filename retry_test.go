@@ -0,0 +1,112 @@
+package rds_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rdsdata"
+	"github.com/aws/aws-sdk-go-v2/service/rdsdata/types"
+	"github.com/aws/smithy-go"
+	"github.com/golang/mock/gomock"
+	"github.com/krotscheck/go-rds-driver"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func Test_RunInTx(t *testing.T) {
+	ctx := context.Background()
+	conf := rds.NewConfig("resourceARN", "secretARN", "database", "region")
+	deadlock := fmt.Errorf("BadRequestException: Deadlock found when trying to get lock; try restarting transaction")
+
+	Convey("IsRetryable", t, func() {
+		So(rds.IsRetryable(&rds.DatabaseError{Kind: rds.ErrSerializationFailure, Err: deadlock}), ShouldBeTrue)
+		So(rds.IsRetryable(&rds.DatabaseError{Kind: rds.ErrDeadlock, Err: deadlock}), ShouldBeTrue)
+		So(rds.IsRetryable(&rds.DatabaseError{Kind: rds.ErrCheckViolation, Err: deadlock}), ShouldBeFalse)
+		So(rds.IsRetryable(rds.ErrTransactionExpired), ShouldBeTrue)
+		So(rds.IsRetryable(&types.DatabaseResumingException{Message: aws.String("resuming")}), ShouldBeTrue)
+		So(rds.IsRetryable(&smithy.GenericAPIError{Code: "ThrottlingException"}), ShouldBeTrue)
+		So(rds.IsRetryable(&types.BadRequestException{Message: aws.String("syntax error")}), ShouldBeFalse)
+	})
+
+	Convey("RunInTx", t, func() {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		mockRDS := NewMockAWSClientInterface(ctrl)
+		ExpectWakeup(mockRDS, conf)
+
+		db := sql.OpenDB(rds.NewConnector(rds.NewDriver(), mockRDS, conf))
+		defer db.Close()
+
+		var attempts []error
+		var retries []time.Duration
+		policy := &rds.RetryPolicy{
+			MaxAttempts:    3,
+			InitialBackoff: time.Millisecond,
+			MaxBackoff:     2 * time.Millisecond,
+			OnAttempt: func(attempt int, err error) {
+				attempts = append(attempts, err)
+			},
+			OnRetry: func(attempt int, err error, backoff time.Duration) {
+				retries = append(retries, backoff)
+			},
+		}
+
+		insert := func(tx *sql.Tx) error {
+			_, err := tx.ExecContext(ctx, "INSERT INTO accounts VALUES (1)")
+			return err
+		}
+
+		Convey("Commits on success", func() {
+			ExpectBeginTransaction(mockRDS, "transactionID")
+			ExpectStatement(mockRDS, "INSERT INTO accounts VALUES (1)").Return(&rdsdata.ExecuteStatementOutput{}, nil)
+			mockRDS.EXPECT().CommitTransaction(gomock.Any(), gomock.Any()).Return(&rdsdata.CommitTransactionOutput{}, nil)
+
+			So(policy.RunInTx(ctx, db, nil, insert), ShouldBeNil)
+			So(attempts, ShouldResemble, []error{nil})
+		})
+
+		Convey("Retries deadlocks", func() {
+			ExpectBeginTransaction(mockRDS, "transactionID")
+			ExpectBeginTransaction(mockRDS, "transactionID")
+			gomock.InOrder(
+				ExpectStatement(mockRDS, "INSERT INTO accounts VALUES (1)").Return(nil, deadlock),
+				ExpectStatement(mockRDS, "INSERT INTO accounts VALUES (1)").Return(&rdsdata.ExecuteStatementOutput{}, nil),
+			)
+			mockRDS.EXPECT().RollbackTransaction(gomock.Any(), gomock.Any()).Return(&rdsdata.RollbackTransactionOutput{}, nil)
+			mockRDS.EXPECT().CommitTransaction(gomock.Any(), gomock.Any()).Return(&rdsdata.CommitTransactionOutput{}, nil)
+
+			So(policy.RunInTx(ctx, db, nil, insert), ShouldBeNil)
+			So(len(attempts), ShouldEqual, 2)
+			So(errors.Is(attempts[0], rds.ErrDeadlock), ShouldBeTrue)
+			So(len(retries), ShouldEqual, 1)
+			So(retries[0], ShouldBeLessThanOrEqualTo, time.Millisecond)
+		})
+
+		Convey("Gives up after the maximum attempts", func() {
+			ExpectBeginTransaction(mockRDS, "transactionID").Times(3)
+			ExpectStatement(mockRDS, "INSERT INTO accounts VALUES (1)").Times(3).Return(nil, deadlock)
+			mockRDS.EXPECT().RollbackTransaction(gomock.Any(), gomock.Any()).Times(3).Return(&rdsdata.RollbackTransactionOutput{}, nil)
+
+			err := policy.RunInTx(ctx, db, nil, insert)
+			So(errors.Is(err, rds.ErrDeadlock), ShouldBeTrue)
+			So(len(attempts), ShouldEqual, 3)
+			So(len(retries), ShouldEqual, 2)
+		})
+
+		Convey("Doesn't retry other errors", func() {
+			ExpectBeginTransaction(mockRDS, "transactionID")
+			mockRDS.EXPECT().RollbackTransaction(gomock.Any(), gomock.Any()).Return(&rdsdata.RollbackTransactionOutput{}, nil)
+
+			expected := fmt.Errorf("application error")
+			err := policy.RunInTx(ctx, db, nil, func(tx *sql.Tx) error {
+				return expected
+			})
+			So(err, ShouldEqual, expected)
+			So(len(attempts), ShouldEqual, 1)
+		})
+	})
+}
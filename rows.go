@@ -1,10 +1,13 @@
 package rds
 
 import (
+	"context"
 	"database/sql/driver"
 	"fmt"
 	"io"
+	"log/slog"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/rdsdata"
 	"github.com/aws/aws-sdk-go-v2/service/rdsdata/types"
 )
@@ -30,11 +33,41 @@ func NewRows(dialect Dialect, results []*rdsdata.ExecuteStatementOutput) driver.
 	return rows
 }
 
+// newQueryRows for the outputs of a query's statements. Unless includeDML is set, outputs of
+// statements which returned no columns, such as an UPDATE, aren't returned as result sets.
+func newQueryRows(dialect Dialect, results []*rdsdata.ExecuteStatementOutput, includeDML bool) *Rows {
+	updateCounts := make([]int64, len(results))
+	for i, result := range results {
+		updateCounts[i] = result.NumberOfRecordsUpdated
+	}
+
+	if !includeDML {
+		var resultSets []*rdsdata.ExecuteStatementOutput
+		for _, result := range results {
+			if len(result.ColumnMetadata) > 0 {
+				resultSets = append(resultSets, result)
+			}
+		}
+		if len(resultSets) == 0 && len(results) > 0 {
+			// Return an empty result set rather than none at all.
+			resultSets = results[len(results)-1:]
+		}
+		results = resultSets
+	}
+
+	rows := NewRows(dialect, results).(*Rows)
+	rows.updateCounts = updateCounts
+	return rows
+}
+
 // Rows implementation for the RDS Driver
 type Rows struct {
 	dialect        Dialect
 	resultPosition int
 	results        []*rdsdata.ExecuteStatementOutput
+	updateCounts   []int64
+	ctx            context.Context
+	log            *eventLogger
 
 	columnNames    []string
 	converters     []FieldConverter
@@ -63,11 +96,24 @@ func (r *Rows) setResultIndex(i int) {
 	r.converters = make([]FieldConverter, len(curr.ColumnMetadata))
 	r.columnNames = make([]string, len(curr.ColumnMetadata))
 	for i, col := range curr.ColumnMetadata {
-		r.converters[i] = r.dialect.GetFieldConverter(*col.TypeName)
+		r.converters[i] = r.dialect.GetFieldConverter(col)
 		r.columnNames[i] = *col.Label
 	}
 }
 
+// UpdateCounts returns the number of records updated by each statement of the query, in order,
+// including those which aren't returned as result sets.
+func (r *Rows) UpdateCounts() []int64 {
+	if r.updateCounts == nil {
+		updateCounts := make([]int64, len(r.results))
+		for i, result := range r.results {
+			updateCounts[i] = result.NumberOfRecordsUpdated
+		}
+		return updateCounts
+	}
+	return r.updateCounts
+}
+
 // Columns returns the column names in order
 func (r *Rows) Columns() []string {
 	return r.columnNames
@@ -102,7 +148,7 @@ func (r *Rows) Next(dest []driver.Value) error {
 		coerced, err := converter(field)
 
 		if err != nil {
-			fmt.Printf("Metadata for failed column: %#v", curr.ColumnMetadata[i])
+			r.logConversionFailure(i, err)
 			return fmt.Errorf("convertValue(col=%d): %v", i, err)
 		}
 
@@ -111,3 +157,17 @@ func (r *Rows) Next(dest []driver.Value) error {
 
 	return nil
 }
+
+func (r *Rows) logConversionFailure(i int, err error) {
+	ctx := r.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	column := r.results[r.resultPosition].ColumnMetadata[i]
+	r.log.conversionFailure(ctx, "rds: failed to convert field",
+		slog.Int("column", i),
+		slog.String("label", aws.ToString(column.Label)),
+		slog.String("type_name", aws.ToString(column.TypeName)),
+		slog.Int("type", int(column.Type)),
+		slog.Any("error", err))
+}
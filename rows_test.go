@@ -1,8 +1,12 @@
 package rds_test
 
 import (
+	"context"
 	"database/sql/driver"
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/rdsdata"
+	"github.com/aws/aws-sdk-go-v2/service/rdsdata/types"
+	"github.com/golang/mock/gomock"
 	"github.com/aws/smithy-go/middleware"
 	"github.com/krotscheck/go-rds-driver"
 	. "github.com/smartystreets/goconvey/convey"
@@ -50,4 +54,44 @@ func Test_Rows(t *testing.T) {
 		So(rowResult.HasNextResultSet(), ShouldBeFalse)
 		So(rowResult.NextResultSet(), ShouldEqual, io.EOF)
 	})
+
+	Convey("Query with DML statements", t, func() {
+		ctx := context.Background()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		mockRDS := NewMockAWSClientInterface(ctrl)
+		conf := rds.NewConfig("resourceARN", "secretARN", "database", "region")
+		conf.SplitMulti = true
+
+		selectOutput := &rdsdata.ExecuteStatementOutput{
+			ColumnMetadata: []types.ColumnMetadata{{Label: aws.String("id"), TypeName: aws.String("INT")}},
+			Records:        [][]types.Field{{&types.FieldMemberLongValue{Value: 1}}},
+		}
+		ExpectStatement(mockRDS, "UPDATE a SET name = 'one'").Return(&rdsdata.ExecuteStatementOutput{NumberOfRecordsUpdated: 3}, nil)
+		ExpectStatement(mockRDS, "SELECT id FROM a").Return(selectOutput, nil)
+
+		Convey("Skips DML statements by default", func() {
+			conn := rds.NewConnection(ctx, mockRDS, conf, rds.NewMySQL(conf)).(*rds.Connection)
+			rows, err := conn.QueryContext(ctx, "UPDATE a SET name = 'one'; SELECT id FROM a", nil)
+			So(err, ShouldBeNil)
+
+			So(rows.Columns(), ShouldResemble, []string{"id"})
+			dest := make([]driver.Value, 1)
+			So(rows.Next(dest), ShouldBeNil)
+			So(dest[0], ShouldEqual, 1)
+			So(rows.(driver.RowsNextResultSet).HasNextResultSet(), ShouldBeFalse)
+			So(rows.(*rds.Rows).UpdateCounts(), ShouldResemble, []int64{3, 0})
+		})
+
+		Convey("Includes DML statements if configured", func() {
+			conf.DMLResultSets = true
+			conn := rds.NewConnection(ctx, mockRDS, conf, rds.NewMySQL(conf)).(*rds.Connection)
+			rows, err := conn.QueryContext(ctx, "UPDATE a SET name = 'one'; SELECT id FROM a", nil)
+			So(err, ShouldBeNil)
+
+			So(rows.Columns(), ShouldBeEmpty)
+			So(rows.(driver.RowsNextResultSet).NextResultSet(), ShouldBeNil)
+			So(rows.Columns(), ShouldResemble, []string{"id"})
+		})
+	})
 }
@@ -0,0 +1,91 @@
+package rds
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"regexp"
+)
+
+var _ driver.Tx = (*SavepointTx)(nil) // explicit compile time type check
+
+var savepointNameRegex = regexp.MustCompile("^[A-Za-z_][A-Za-z0-9_]*$")
+
+// Savepoint creates a named savepoint in the transaction currently open on the passed connection.
+// Commit on the returned transaction releases the savepoint, while Rollback rolls back to it.
+func Savepoint(ctx context.Context, conn *sql.Conn, name string) (sp *SavepointTx, err error) {
+	err = conn.Raw(func(driverConn interface{}) error {
+		rdsConn, ok := driverConn.(*Connection)
+		if !ok {
+			return fmt.Errorf("savepoints require an %s connection", DRIVERNAME)
+		}
+		sp, err = rdsConn.Savepoint(ctx, name)
+		return err
+	})
+	return
+}
+
+// Savepoint creates a named savepoint in the transaction currently open on this connection.
+func (r *Connection) Savepoint(ctx context.Context, name string) (*SavepointTx, error) {
+	if err := r.rollbackCancelled(); err != nil {
+		return nil, err
+	}
+	if r.tx == nil {
+		return nil, ErrNoTransaction
+	}
+	return r.tx.Savepoint(ctx, name)
+}
+
+// Savepoint creates a named savepoint within this transaction.
+func (r *Tx) Savepoint(ctx context.Context, name string) (*SavepointTx, error) {
+	if r.Done {
+		return nil, sql.ErrTxDone
+	}
+	if !savepointNameRegex.MatchString(name) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidSavepointName, name)
+	}
+	if _, err := r.conn.ExecContext(ctx, savepointerOf(r.conn.dialect).GetSavepointQuery(name), nil); err != nil {
+		return nil, err
+	}
+	return &SavepointTx{
+		Name: name,
+		ctx:  ctx,
+		tx:   r,
+	}, nil
+}
+
+// Begin a nested transaction, backed by a savepoint with a generated name.
+func (r *Tx) Begin(ctx context.Context) (driver.Tx, error) {
+	r.savepoints++
+	return r.Savepoint(ctx, fmt.Sprintf("rds_savepoint_%d", r.savepoints))
+}
+
+// SavepointTx is a nested transaction, backed by a savepoint within a Tx.
+type SavepointTx struct {
+	Name string
+	Done bool
+	ctx  context.Context
+	tx   *Tx
+}
+
+// Commit the nested transaction by releasing its savepoint.
+func (s *SavepointTx) Commit() error {
+	return s.exec(savepointerOf(s.tx.conn.dialect).GetReleaseSavepointQuery(s.Name))
+}
+
+// Rollback the nested transaction to its savepoint, leaving the enclosing transaction open.
+func (s *SavepointTx) Rollback() error {
+	return s.exec(savepointerOf(s.tx.conn.dialect).GetRollbackToSavepointQuery(s.Name))
+}
+
+func (s *SavepointTx) exec(query string) error {
+	if s.Done || s.tx.Done {
+		return sql.ErrTxDone
+	}
+	if _, err := s.tx.conn.ExecContext(s.ctx, query, nil); err != nil {
+		return err
+	}
+	s.Done = true
+	return nil
+}
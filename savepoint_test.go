@@ -0,0 +1,120 @@
+package rds_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rdsdata"
+	"github.com/golang/mock/gomock"
+	"github.com/krotscheck/go-rds-driver"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func Test_Savepoint(t *testing.T) {
+	ctx := context.Background()
+	conf := rds.NewConfig("resourceARN", "secretARN", "database", "region")
+
+	Convey("Savepoint", t, func() {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		mockRDS := NewMockAWSClientInterface(ctrl)
+		conn := rds.NewConnection(ctx, mockRDS, conf, rds.NewMySQL(conf)).(*rds.Connection)
+
+		Convey("Without a transaction", func() {
+			_, err := conn.Savepoint(ctx, "outer")
+			So(err, ShouldEqual, rds.ErrNoTransaction)
+		})
+
+		Convey("Within a transaction", func() {
+			ExpectBeginTransaction(mockRDS, "transactionID")
+			tx, err := conn.BeginTx(ctx, driver.TxOptions{})
+			So(err, ShouldBeNil)
+
+			Convey("Invalid name", func() {
+				_, err := conn.Savepoint(ctx, "outer; DROP TABLE users")
+				So(errors.Is(err, rds.ErrInvalidSavepointName), ShouldBeTrue)
+			})
+
+			Convey("Release", func() {
+				ExpectStatement(mockRDS, "SAVEPOINT `outer`").
+					Do(func(_ context.Context, input *rdsdata.ExecuteStatementInput, _ ...func(*rdsdata.Options)) {
+						So(aws.ToString(input.TransactionId), ShouldEqual, "transactionID")
+					}).
+					Return(&rdsdata.ExecuteStatementOutput{}, nil)
+				ExpectStatement(mockRDS, "RELEASE SAVEPOINT `outer`").Return(&rdsdata.ExecuteStatementOutput{}, nil)
+
+				sp, err := conn.Savepoint(ctx, "outer")
+				So(err, ShouldBeNil)
+				So(sp.Commit(), ShouldBeNil)
+				So(sp.Commit(), ShouldEqual, sql.ErrTxDone)
+			})
+
+			Convey("Rollback", func() {
+				ExpectStatement(mockRDS, "SAVEPOINT `outer`").Return(&rdsdata.ExecuteStatementOutput{}, nil)
+				ExpectStatement(mockRDS, "ROLLBACK TO SAVEPOINT `outer`").Return(&rdsdata.ExecuteStatementOutput{}, nil)
+
+				sp, err := conn.Savepoint(ctx, "outer")
+				So(err, ShouldBeNil)
+				So(sp.Rollback(), ShouldBeNil)
+			})
+
+			Convey("Nested BeginTx", func() {
+				ExpectStatement(mockRDS, "SAVEPOINT `rds_savepoint_1`").Return(&rdsdata.ExecuteStatementOutput{}, nil)
+				ExpectStatement(mockRDS, "SAVEPOINT `rds_savepoint_2`").Return(&rdsdata.ExecuteStatementOutput{}, nil)
+				ExpectStatement(mockRDS, "ROLLBACK TO SAVEPOINT `rds_savepoint_2`").Return(&rdsdata.ExecuteStatementOutput{}, nil)
+				ExpectStatement(mockRDS, "RELEASE SAVEPOINT `rds_savepoint_1`").Return(&rdsdata.ExecuteStatementOutput{}, nil)
+				mockRDS.EXPECT().CommitTransaction(gomock.Any(), gomock.Any()).Return(&rdsdata.CommitTransactionOutput{}, nil)
+
+				nested, err := conn.BeginTx(ctx, driver.TxOptions{})
+				So(err, ShouldBeNil)
+				inner, err := conn.BeginTx(ctx, driver.TxOptions{})
+				So(err, ShouldBeNil)
+
+				So(inner.Rollback(), ShouldBeNil)
+				So(nested.Commit(), ShouldBeNil)
+				So(tx.Commit(), ShouldBeNil)
+			})
+
+			Convey("Nested BeginTx with options", func() {
+				_, err := conn.BeginTx(ctx, driver.TxOptions{ReadOnly: true})
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("After the transaction completes", func() {
+				ExpectStatement(mockRDS, "SAVEPOINT `outer`").Return(&rdsdata.ExecuteStatementOutput{}, nil)
+				mockRDS.EXPECT().RollbackTransaction(gomock.Any(), gomock.Any()).Return(&rdsdata.RollbackTransactionOutput{}, nil)
+
+				sp, err := conn.Savepoint(ctx, "outer")
+				So(err, ShouldBeNil)
+				So(tx.Rollback(), ShouldBeNil)
+				So(sp.Commit(), ShouldEqual, sql.ErrTxDone)
+			})
+		})
+
+		Convey("Through sql.Conn", func() {
+			ExpectWakeup(mockRDS, conf)
+			ExpectBeginTransaction(mockRDS, "transactionID")
+			ExpectStatement(mockRDS, "SAVEPOINT `outer`").Return(&rdsdata.ExecuteStatementOutput{}, nil)
+			ExpectStatement(mockRDS, "RELEASE SAVEPOINT `outer`").Return(&rdsdata.ExecuteStatementOutput{}, nil)
+			mockRDS.EXPECT().CommitTransaction(gomock.Any(), gomock.Any()).Return(&rdsdata.CommitTransactionOutput{}, nil)
+
+			db := sql.OpenDB(rds.NewConnector(rds.NewDriver(), mockRDS, conf))
+			defer db.Close()
+			sqlConn, err := db.Conn(ctx)
+			So(err, ShouldBeNil)
+			defer sqlConn.Close()
+
+			tx, err := sqlConn.BeginTx(ctx, nil)
+			So(err, ShouldBeNil)
+
+			sp, err := rds.Savepoint(ctx, sqlConn, "outer")
+			So(err, ShouldBeNil)
+			So(sp.Commit(), ShouldBeNil)
+			So(tx.Commit(), ShouldBeNil)
+		})
+	})
+}
@@ -2,7 +2,9 @@ package rds
 
 import (
 	"context"
+	"database/sql"
 	"database/sql/driver"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/rdsdata"
@@ -48,16 +50,25 @@ func (s *Statement) Exec(values []driver.Value) (driver.Result, error) {
 }
 
 // ExecContext executes a queries that doesn't return rows, such as an INSERT or UPDATE.
+// The returned result is a *MultiResult.
 func (s *Statement) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
-	var output []*rdsdata.ExecuteStatementOutput
-	for _, query := range s.queries {
-		out, err := s.executeStatement(ctx, query, args)
-		if err != nil {
-			return nil, err
-		}
-		output = append(output, out)
+	result, err := s.exec(ctx, args)
+	if err != nil {
+		return nil, err
 	}
-	return NewResult(output), nil
+	return result, nil
+}
+
+func (s *Statement) exec(ctx context.Context, args []driver.NamedValue) (*MultiResult, error) {
+	output, err := s.executeAll(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	result, err := NewMultiResult(s.conn.dialect, s.queries, output)
+	if err != nil || !s.conn.captureReturning {
+		return result, err
+	}
+	return result, result.captureReturning(s.conn.dialect, output)
 }
 
 // Query executes a queries that may return rows, such as a SELECT.
@@ -69,15 +80,14 @@ func (s *Statement) Query(values []driver.Value) (driver.Rows, error) {
 
 // QueryContext executes a queries that may return rows, such as a SELECT.
 func (s *Statement) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
-	var output []*rdsdata.ExecuteStatementOutput
-	for _, query := range s.queries {
-		out, err := s.executeStatement(ctx, query, args)
-		if err != nil {
-			return nil, err
-		}
-		output = append(output, out)
+	output, err := s.executeAll(ctx, args)
+	if err != nil {
+		return nil, err
 	}
-	return NewRows(s.conn.dialect, output), nil
+	rows := newQueryRows(s.conn.dialect, output, s.conn.dmlResultSets)
+	rows.ctx = ctx
+	rows.log = s.conn.log
+	return rows, nil
 }
 
 // ConvertOrdinal converts a list of Values to Ordinal NamedValues
@@ -94,20 +104,86 @@ func (s *Statement) ConvertOrdinal(values []driver.Value) []driver.NamedValue {
 	return namedValues
 }
 
-func (s *Statement) executeStatement(ctx context.Context, query string, values []driver.NamedValue) (*rdsdata.ExecuteStatementOutput, error) {
+// executeAll queries in order. If the connection is configured for atomic_multi and no transaction
+// is open, multiple queries are wrapped in a transaction that's rolled back on the first failure.
+func (s *Statement) executeAll(ctx context.Context, args []driver.NamedValue) ([]*rdsdata.ExecuteStatementOutput, error) {
+	if err := s.conn.rollbackCancelled(); err != nil {
+		return nil, err
+	}
+	if !s.conn.atomicMulti || s.conn.tx != nil || len(s.queries) < 2 {
+		var output []*rdsdata.ExecuteStatementOutput
+		for i, query := range s.queries {
+			out, err := s.executeStatement(ctx, i, query, args)
+			if err != nil {
+				return nil, err
+			}
+			output = append(output, out)
+		}
+		return output, nil
+	}
+
+	tx, err := s.conn.BeginTx(ctx, driver.TxOptions{Isolation: driver.IsolationLevel(sql.LevelDefault)})
+	if err != nil {
+		return nil, err
+	}
+	var output []*rdsdata.ExecuteStatementOutput
+	for i, query := range s.queries {
+		out, err := s.executeStatement(ctx, i, query, args)
+		if err != nil {
+			return nil, &MultiStatementError{RollbackErr: tx.Rollback(), Err: err}
+		}
+		output = append(output, out)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, &MultiStatementError{Commit: true, Err: err}
+	}
+	return output, nil
+}
+
+func (s *Statement) executeStatement(ctx context.Context, index int, query string, values []driver.NamedValue) (*rdsdata.ExecuteStatementOutput, error) {
 	input, err := s.conn.dialect.MigrateQuery(query, values)
 
 	if err != nil {
 		return nil, err
 	}
 
-	if s.conn.tx != nil {
-		input.TransactionId = s.conn.tx.TransactionID
+	tx := s.conn.tx
+	if tx != nil {
+		if err := tx.checkExpired(); err != nil {
+			return nil, err
+		}
+		tx.busy.Lock()
+		defer tx.busy.Unlock()
+		input.TransactionId = tx.TransactionID
 	}
 
 	input.IncludeResultMetadata = true
 	input.ResourceArn = aws.String(s.conn.resourceARN)
 	input.SecretArn = aws.String(s.conn.secretARN)
 	input.Database = aws.String(s.conn.database)
-	return s.conn.rds.ExecuteStatement(ctx, input)
+	start := time.Now()
+	output, err := s.conn.executeStatement(ctx, input)
+	if tx != nil && isTransactionNotFound(err) {
+		err = tx.expire(err)
+	} else if err != nil {
+		err = translateError(s.conn.dialect, err)
+	}
+	if err != nil {
+		err = &StatementError{
+			Query:         query,
+			Fingerprint:   FingerprintQuery(s.conn.dialect, query),
+			Index:         index,
+			TransactionID: aws.ToString(input.TransactionId),
+			RequestID:     requestID(err),
+			Err:           err,
+		}
+	}
+	s.conn.logQuery(ctx, query, values, time.Since(start), output, err)
+	if err != nil {
+		return nil, err
+	}
+	if tx != nil {
+		tx.touch()
+	}
+	return output, nil
 }
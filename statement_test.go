@@ -3,9 +3,17 @@ package rds_test
 import (
 	"context"
 	"database/sql/driver"
+	"errors"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/service/rdsdata"
+	"github.com/aws/aws-sdk-go-v2/service/rdsdata/types"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
 	"github.com/golang/mock/gomock"
 	"github.com/krotscheck/go-rds-driver"
 	. "github.com/smartystreets/goconvey/convey"
+	"net/http"
+	"strings"
 	"testing"
 )
 
@@ -44,4 +52,121 @@ func Test_Statement(t *testing.T) {
 			})
 		})
 	})
+
+	Convey("Atomic Multi", t, func() {
+		contrl := gomock.NewController(t)
+		defer contrl.Finish()
+		mockRDS := NewMockAWSClientInterface(contrl)
+		atomicConf := rds.NewConfig("resourceARN", "secretARN", "database", "region")
+		atomicConf.SplitMulti = true
+		atomicConf.AtomicMulti = true
+		conn := rds.NewConnection(ctx, mockRDS, atomicConf, rds.NewMySQL(atomicConf)).(*rds.Connection)
+		migration := "CREATE TABLE a (id INT); CREATE TABLE b (id INT)"
+
+		Convey("Commits once all fragments succeed", func() {
+			ExpectBeginTransaction(mockRDS, "transactionID")
+			ExpectStatement(mockRDS, "CREATE TABLE a (id INT)").
+				DoAndReturn(func(_ context.Context, input *rdsdata.ExecuteStatementInput, _ ...func(*rdsdata.Options)) (*rdsdata.ExecuteStatementOutput, error) {
+					So(aws.ToString(input.TransactionId), ShouldEqual, "transactionID")
+					return &rdsdata.ExecuteStatementOutput{}, nil
+				})
+			ExpectStatement(mockRDS, "CREATE TABLE b (id INT)").Return(&rdsdata.ExecuteStatementOutput{}, nil)
+			mockRDS.EXPECT().CommitTransaction(gomock.Any(), gomock.Any()).Return(&rdsdata.CommitTransactionOutput{}, nil)
+
+			_, err := conn.ExecContext(ctx, migration, nil)
+			So(err, ShouldBeNil)
+			_, err = conn.CurrentTx()
+			So(err, ShouldEqual, rds.ErrNoTransaction)
+		})
+
+		Convey("Rolls back on the first failure", func() {
+			failure := errors.New("table b already exists")
+			ExpectBeginTransaction(mockRDS, "transactionID")
+			ExpectStatement(mockRDS, "CREATE TABLE a (id INT)").Return(&rdsdata.ExecuteStatementOutput{}, nil)
+			ExpectStatement(mockRDS, "CREATE TABLE b (id INT)").Return(nil, failure)
+			mockRDS.EXPECT().RollbackTransaction(gomock.Any(), gomock.Any()).Return(&rdsdata.RollbackTransactionOutput{}, nil)
+
+			_, err := conn.ExecContext(ctx, migration, nil)
+			var multiErr *rds.MultiStatementError
+			So(errors.As(err, &multiErr), ShouldBeTrue)
+			So(multiErr.Commit, ShouldBeFalse)
+			So(multiErr.RollbackErr, ShouldBeNil)
+			var stmtErr *rds.StatementError
+			So(errors.As(err, &stmtErr), ShouldBeTrue)
+			So(stmtErr.Index, ShouldEqual, 1)
+			So(stmtErr.Query, ShouldEqual, "CREATE TABLE b (id INT)")
+			So(errors.Is(err, failure), ShouldBeTrue)
+			So(strings.Count(err.Error(), "statement=1"), ShouldEqual, 1)
+		})
+
+		Convey("Reports a failed commit", func() {
+			failure := errors.New("commit failed")
+			ExpectBeginTransaction(mockRDS, "transactionID")
+			ExpectStatement(mockRDS, "CREATE TABLE a (id INT)").Return(&rdsdata.ExecuteStatementOutput{}, nil)
+			ExpectStatement(mockRDS, "CREATE TABLE b (id INT)").Return(&rdsdata.ExecuteStatementOutput{}, nil)
+			mockRDS.EXPECT().CommitTransaction(gomock.Any(), gomock.Any()).Return(nil, failure)
+
+			_, err := conn.ExecContext(ctx, migration, nil)
+			var multiErr *rds.MultiStatementError
+			So(errors.As(err, &multiErr), ShouldBeTrue)
+			So(multiErr.Commit, ShouldBeTrue)
+			So(errors.Is(err, failure), ShouldBeTrue)
+		})
+
+		Convey("Joins an open transaction", func() {
+			ExpectBeginTransaction(mockRDS, "transactionID")
+			tx, err := conn.BeginTx(ctx, driver.TxOptions{})
+			So(err, ShouldBeNil)
+
+			ExpectStatement(mockRDS, "CREATE TABLE a (id INT)").Return(&rdsdata.ExecuteStatementOutput{}, nil)
+			ExpectStatement(mockRDS, "CREATE TABLE b (id INT)").Return(&rdsdata.ExecuteStatementOutput{}, nil)
+			_, err = conn.ExecContext(ctx, migration, nil)
+			So(err, ShouldBeNil)
+
+			mockRDS.EXPECT().CommitTransaction(gomock.Any(), gomock.Any()).Return(&rdsdata.CommitTransactionOutput{}, nil)
+			So(tx.Commit(), ShouldBeNil)
+		})
+	})
+
+	Convey("Statement Errors", t, func() {
+		contrl := gomock.NewController(t)
+		defer contrl.Finish()
+		mockRDS := NewMockAWSClientInterface(contrl)
+		splitConf := rds.NewConfig("resourceARN", "secretARN", "database", "region")
+		splitConf.SplitMulti = true
+		conn := rds.NewConnection(ctx, mockRDS, splitConf, rds.NewMySQL(splitConf)).(*rds.Connection)
+
+		ExpectBeginTransaction(mockRDS, "transactionID")
+		tx, err := conn.BeginTx(ctx, driver.TxOptions{})
+		So(err, ShouldBeNil)
+		defer func() {
+			mockRDS.EXPECT().RollbackTransaction(gomock.Any(), gomock.Any()).Return(&rdsdata.RollbackTransactionOutput{}, nil)
+			_ = tx.Rollback()
+		}()
+
+		ExpectStatement(mockRDS, "SELECT 1").Return(&rdsdata.ExecuteStatementOutput{}, nil)
+		ExpectStatement(mockRDS, "UPDATE a SET b = 2").Return(nil, &awshttp.ResponseError{
+			ResponseError: &smithyhttp.ResponseError{
+				Response: &smithyhttp.Response{Response: &http.Response{StatusCode: http.StatusBadRequest}},
+				Err:      &types.BadRequestException{Message: aws.String("Deadlock found when trying to get lock; try restarting transaction")},
+			},
+			RequestID: "requestID",
+		})
+
+		_, err = conn.ExecContext(ctx, "SELECT 1; UPDATE a SET b = 2", nil)
+		var stmtErr *rds.StatementError
+		So(errors.As(err, &stmtErr), ShouldBeTrue)
+		So(stmtErr.Query, ShouldEqual, "UPDATE a SET b = 2")
+		So(stmtErr.Fingerprint, ShouldEqual, rds.FingerprintQuery(rds.NewMySQL(splitConf), "UPDATE a SET b = 2"))
+		So(stmtErr.Index, ShouldEqual, 1)
+		So(stmtErr.TransactionID, ShouldEqual, "transactionID")
+		So(stmtErr.RequestID, ShouldEqual, "requestID")
+		So(err.Error(), ShouldEndWith, "[request_id=requestID transaction_id=transactionID statement=1 fingerprint="+stmtErr.Fingerprint+"]")
+
+		So(errors.Is(err, rds.ErrDeadlock), ShouldBeTrue)
+		var badRequest *types.BadRequestException
+		So(errors.As(err, &badRequest), ShouldBeTrue)
+		var dbErr *rds.DatabaseError
+		So(errors.As(err, &dbErr), ShouldBeTrue)
+	})
 }
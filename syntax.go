@@ -0,0 +1,220 @@
+package rds
+
+import (
+	"strings"
+)
+
+// sqlSyntax of the literals and comments of a dialect, within which queries are neither rewritten nor split.
+type sqlSyntax struct {
+	// quotes delimiting strings and identifiers, which are escaped by doubling them.
+	quotes string
+	// backslashEscapes within quoted strings, as in MySQL.
+	backslashEscapes bool
+	// escapeStrings, prefixed with E, within which backslashes escape, as in Postgres.
+	escapeStrings bool
+	// dollarQuotes delimiting strings, such as $tag$...$tag$ in Postgres.
+	dollarQuotes bool
+	// hashComments running to the end of the line, as in MySQL.
+	hashComments bool
+	// dashCommentsNeedSpace after the --, as in MySQL, where 1--1 is arithmetic.
+	dashCommentsNeedSpace bool
+	// nestedComments, as in Postgres.
+	nestedComments bool
+}
+
+var mysqlSyntax = &sqlSyntax{
+	quotes:                "'\"`",
+	backslashEscapes:      true,
+	hashComments:          true,
+	dashCommentsNeedSpace: true,
+}
+
+var postgresSyntax = &sqlSyntax{
+	quotes:         "'\"",
+	escapeStrings:  true,
+	dollarQuotes:   true,
+	nestedComments: true,
+}
+
+// syntaxer is implemented by dialects which know the syntax of their literals and comments.
+type syntaxer interface {
+	syntax() *sqlSyntax
+}
+
+// syntaxOf the dialect, defaulting to that of MySQL.
+func syntaxOf(dialect Dialect) *sqlSyntax {
+	if s, ok := dialect.(syntaxer); ok {
+		return s.syntax()
+	}
+	return mysqlSyntax
+}
+
+// split the query into its statements at the semicolons outside its literals and comments. Joined with
+// semicolons, the statements are the query again.
+func (s *sqlSyntax) split(query string) []string {
+	var statements []string
+	start := 0
+	for i := 0; i < len(query); {
+		if next := s.skip(query, i); next > i {
+			i = next
+			continue
+		}
+		if query[i] == ';' {
+			statements = append(statements, query[start:i])
+			start = i + 1
+		}
+		i++
+	}
+	return append(statements, query[start:])
+}
+
+// replacePlaceholders outside the literals and comments of the query. At every other position, the
+// placeholder function returns the replacement of the placeholder starting there and its length, if any.
+func (s *sqlSyntax) replacePlaceholders(query string, placeholder func(query string, i int) (string, int)) string {
+	var b strings.Builder
+	last := 0
+	for i := 0; i < len(query); {
+		if next := s.skip(query, i); next > i {
+			i = next
+			continue
+		}
+		if replacement, n := placeholder(query, i); n > 0 {
+			b.WriteString(query[last:i])
+			b.WriteString(replacement)
+			i += n
+			last = i
+			continue
+		}
+		i++
+	}
+	b.WriteString(query[last:])
+	return b.String()
+}
+
+// transform the literals and comments of the query, and the text between them, with the passed functions.
+func (s *sqlSyntax) transform(query string, literal func(literal string) string, text func(text string) string) string {
+	var b strings.Builder
+	last := 0
+	for i := 0; i < len(query); {
+		next := s.skip(query, i)
+		if next == i {
+			i++
+			continue
+		}
+		b.WriteString(text(query[last:i]))
+		b.WriteString(literal(query[i:next]))
+		i = next
+		last = i
+	}
+	b.WriteString(text(query[last:]))
+	return b.String()
+}
+
+// skip the literal or comment starting at i, returning the index following it, or i if none starts there.
+// Unterminated literals and comments run to the end of the query.
+func (s *sqlSyntax) skip(query string, i int) int {
+	c := query[i]
+	switch {
+	case strings.IndexByte(s.quotes, c) >= 0:
+		backslashes := c != '`' && (s.backslashEscapes || s.escapeStrings && isEscapeString(query, i))
+		return skipQuoted(query, i, backslashes)
+	case c == '-' && strings.HasPrefix(query[i:], "--"):
+		if s.dashCommentsNeedSpace && len(query) > i+2 && !isSpace(query[i+2]) {
+			return i
+		}
+		return skipLine(query, i)
+	case c == '#' && s.hashComments:
+		return skipLine(query, i)
+	case c == '/' && strings.HasPrefix(query[i:], "/*"):
+		return s.skipComment(query, i)
+	case c == '$' && s.dollarQuotes:
+		tag := dollarTag(query, i)
+		if tag == "" {
+			return i
+		}
+		if end := strings.Index(query[i+len(tag):], tag); end >= 0 {
+			return i + len(tag) + end + len(tag)
+		}
+		return len(query)
+	}
+	return i
+}
+
+// skipComment starting at i with /*.
+func (s *sqlSyntax) skipComment(query string, i int) int {
+	depth := 0
+	for j := i; j < len(query)-1; j++ {
+		switch {
+		case query[j] == '/' && query[j+1] == '*' && (depth == 0 || s.nestedComments):
+			depth++
+			j++
+		case query[j] == '*' && query[j+1] == '/':
+			depth--
+			j++
+			if depth == 0 {
+				return j + 1
+			}
+		}
+	}
+	return len(query)
+}
+
+// skipQuoted literal starting at i with its quote.
+func skipQuoted(query string, i int, backslashes bool) int {
+	quote := query[i]
+	for j := i + 1; j < len(query); j++ {
+		switch {
+		case backslashes && query[j] == '\\':
+			j++
+		case query[j] == quote:
+			if j+1 < len(query) && query[j+1] == quote {
+				j++
+				continue
+			}
+			return j + 1
+		}
+	}
+	return len(query)
+}
+
+// skipLine of the comment starting at i.
+func skipLine(query string, i int) int {
+	if end := strings.IndexByte(query[i:], '\n'); end >= 0 {
+		return i + end + 1
+	}
+	return len(query)
+}
+
+// isEscapeString reports whether the quote at i opens a Postgres escape string, such as E'\n'.
+func isEscapeString(query string, i int) bool {
+	return i > 0 && (query[i-1] == 'E' || query[i-1] == 'e') && (i == 1 || !isIdentifier(query[i-2]))
+}
+
+// dollarTag starting at i, such as $$ or $body$, if any. Tags are not identifiers, so neither $1 nor the
+// dollars within identifiers such as a$b$ start one.
+func dollarTag(query string, i int) string {
+	if i > 0 && isIdentifier(query[i-1]) {
+		return ""
+	}
+	for j := i + 1; j < len(query); j++ {
+		switch c := query[j]; {
+		case c == '$':
+			return query[i : j+1]
+		case c >= '0' && c <= '9':
+			if j == i+1 {
+				return ""
+			}
+		case !isIdentifier(c):
+			return ""
+		}
+	}
+	return ""
+}
+
+func isIdentifier(c byte) bool {
+	return c == '_' || c == '$' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c >= 0x80
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' || c == '\v'
+}
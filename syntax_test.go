@@ -0,0 +1,125 @@
+package rds_test
+
+import (
+	"database/sql/driver"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/krotscheck/go-rds-driver"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// queryFixtures seeding the fuzz targets of the query rewriting and splitting.
+var queryFixtures = []string{
+	MySQLCreateTableQuery,
+	MySQLDropTableQuery,
+	MySQLInsertQuery,
+	PostGreSQLCreateTableQuery,
+	PostgreSQLDropTableQuery,
+	PostgreSQLInsertQuery,
+	"SELECT 1; SELECT 2",
+}
+
+// literalFixtures seeding the fuzz targets, quoted as literals and comments.
+var literalFixtures = []string{
+	"it's",
+	`a \' b; ? $1`,
+	"/* -- */ # \n $$ $q$ `",
+}
+
+// mysqlLiterals quoting the text as every kind of MySQL literal and comment.
+func mysqlLiterals(text string) []string {
+	escaped := strings.ReplaceAll(text, `\`, `\\`)
+	return []string{
+		"'" + strings.ReplaceAll(escaped, "'", "''") + "'",
+		`"` + strings.ReplaceAll(escaped, `"`, `\"`) + `"`,
+		"`" + strings.ReplaceAll(text, "`", "``") + "`",
+		"/* " + removeAll(text, "*/") + " */",
+		"-- " + strings.ReplaceAll(text, "\n", " ") + "\n",
+		"#" + strings.ReplaceAll(text, "\n", " ") + "\n",
+	}
+}
+
+// postgresLiterals quoting the text as every kind of Postgres literal and comment.
+func postgresLiterals(text string) []string {
+	literals := []string{
+		"'" + strings.ReplaceAll(text, "'", "''") + "'",
+		"E'" + strings.ReplaceAll(strings.ReplaceAll(text, `\`, `\\`), "'", `\'`) + "'",
+		`"` + strings.ReplaceAll(text, `"`, `""`) + `"`,
+		"/* " + removeAll(removeAll(text, "*/"), "/*") + " */",
+		"-- " + strings.ReplaceAll(text, "\n", " ") + "\n",
+	}
+	if !strings.Contains(text, "$q$") && !strings.HasSuffix(text, "$q") {
+		literals = append(literals, "$q$"+text+"$q$")
+	}
+	return literals
+}
+
+// removeAll occurrences of the substring, including those created by removing others.
+func removeAll(text string, substring string) string {
+	for strings.Contains(text, substring) {
+		text = strings.ReplaceAll(text, substring, "")
+	}
+	return text
+}
+
+func Test_Syntax(t *testing.T) {
+	mysql := rds.NewMySQL(rds.NewConfig("resourceARN", "secretARN", "database", "region"))
+	postgres := rds.NewPostgres(rds.NewConfig("resourceARN", "secretARN", "database", "region"))
+	args := []driver.NamedValue{{Ordinal: 1, Value: int64(1)}}
+
+	Convey("Splitting", t, func() {
+		So(rds.SplitStatements(mysql, "SELECT ';'; SELECT `a;b` -- c;d\n; # e;f"),
+			ShouldResemble, []string{"SELECT ';'", " SELECT `a;b` -- c;d\n", " # e;f"})
+		So(rds.SplitStatements(mysql, `SELECT 'a\';b'; SELECT 1--1;`),
+			ShouldResemble, []string{`SELECT 'a\';b'`, " SELECT 1--1", ""})
+		So(rds.SplitStatements(postgres, "SELECT $$a;b$$; SELECT $q$;$$;$q$; SELECT /* /* ; */ ; */ 1"),
+			ShouldResemble, []string{"SELECT $$a;b$$", " SELECT $q$;$$;$q$", " SELECT /* /* ; */ ; */ 1"})
+		So(rds.SplitStatements(postgres, `SELECT 'a\'; SELECT E'b\';c'`),
+			ShouldResemble, []string{`SELECT 'a\'`, ` SELECT E'b\';c'`})
+	})
+
+	Convey("Placeholders", t, func() {
+		input, err := mysql.MigrateQuery("SELECT '?', `?`, ? /* ? */, ? -- ?", args)
+		So(err, ShouldBeNil)
+		So(aws.ToString(input.Sql), ShouldEqual, "SELECT '?', `?`, :1 /* ? */, :2 -- ?")
+
+		input, err = postgres.MigrateQuery("SELECT '$1', $$$2$$, $1::int FROM t WHERE x = $2", args)
+		So(err, ShouldBeNil)
+		So(aws.ToString(input.Sql), ShouldEqual, "SELECT '$1', $$$2$$, :1::int FROM t WHERE x = :2")
+	})
+}
+
+func FuzzSplitStatements(f *testing.F) {
+	for _, query := range queryFixtures {
+		for _, literal := range literalFixtures {
+			f.Add(literal, query)
+		}
+	}
+	dialects := map[string]struct {
+		dialect  rds.Dialect
+		literals func(string) []string
+	}{
+		"mysql":    {rds.NewMySQL(rds.NewConfig("", "", "", "")), mysqlLiterals},
+		"postgres": {rds.NewPostgres(rds.NewConfig("", "", "", "")), postgresLiterals},
+	}
+
+	f.Fuzz(func(t *testing.T, literal string, query string) {
+		for name, d := range dialects {
+			statements := rds.SplitStatements(d.dialect, query)
+			if joined := strings.Join(statements, ";"); joined != query {
+				t.Fatalf("%s: %q split into %q, which rejoin to %q", name, query, statements, joined)
+			}
+			if !strings.ContainsAny(query, "'\"`#-/$\\") && len(statements) != strings.Count(query, ";")+1 {
+				t.Fatalf("%s: %q split into %q", name, query, statements)
+			}
+			for _, quoted := range d.literals(literal) {
+				statements := rds.SplitStatements(d.dialect, quoted+";"+query)
+				if statements[0] != quoted {
+					t.Fatalf("%s: %q split within its literal into %q", name, quoted+";"+query, statements)
+				}
+			}
+		}
+	})
+}
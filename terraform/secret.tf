@@ -28,12 +28,18 @@ output "postgresql_resource_arn" {
 output "mysql_resource_arn" {
   value = aws_rds_cluster.test_mysql.arn
 }
+output "mysql8_resource_arn" {
+  value = aws_rds_cluster.test_mysql8.arn
+}
 output "rds_secret_arn" {
   value = aws_secretsmanager_secret.aurora_password.arn
 }
 output "mysql_database_name" {
   value = aws_rds_cluster.test_mysql.database_name
 }
+output "mysql8_database_name" {
+  value = aws_rds_cluster.test_mysql8.database_name
+}
 output "postgresql_database_name" {
   value = aws_rds_cluster.test_postgresql.database_name
 }
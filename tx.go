@@ -4,19 +4,72 @@ import (
 	"context"
 	"database/sql"
 	"database/sql/driver"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/rdsdata"
+	"github.com/aws/aws-sdk-go-v2/service/rdsdata/types"
 )
 
-var _ driver.Tx = (*Tx)(nil) // explicit compile time type check
+var _ driver.Tx = (*Tx)(nil)        // explicit compile time type check
+var _ TransactionHooks = (*Tx)(nil) // explicit compile time type check
+
+// TransactionHooks exposes the outcome of a transaction, and allows code to be run once it's known.
+type TransactionHooks interface {
+	TransactionStatus() string
+	OnCommit(fn func()) error
+	OnRollback(fn func()) error
+}
+
+// CurrentTx returns the transaction currently open on the passed connection, such as to register
+// hooks or read its status once it completes.
+func CurrentTx(conn *sql.Conn) (tx *Tx, err error) {
+	err = conn.Raw(func(driverConn interface{}) error {
+		rdsConn, ok := driverConn.(*Connection)
+		if !ok {
+			return fmt.Errorf("transaction hooks require an %s connection", DRIVERNAME)
+		}
+		tx, err = rdsConn.CurrentTx()
+		return err
+	})
+	return
+}
 
 // NewTx creates a new transaction
 func NewTx(transactionID *string, conn *Connection) driver.Tx {
-	return &Tx{
+	return newTx(context.Background(), transactionID, conn)
+}
+
+// TransactionIdleTimeout after which the Data API terminates a transaction without any activity.
+const TransactionIdleTimeout = 3 * time.Minute
+
+// TransactionMaxLifetime after which the Data API terminates a transaction regardless of activity.
+const TransactionMaxLifetime = 24 * time.Hour
+
+// newTx creates a new transaction bound to the context it was begun with. Should that context be
+// cancelled before the transaction completes, it is rolled back before the connection is next used,
+// unless database/sql has done so already.
+func newTx(ctx context.Context, transactionID *string, conn *Connection) *Tx {
+	tx := &Tx{
 		Done:          false,
 		TransactionID: transactionID,
 		conn:          conn,
+		ctx:           ctx,
+		beganAt:       time.Now(),
+		finished:      make(chan struct{}),
+	}
+	tx.touch()
+	if conn.txKeepAlive > 0 {
+		tx.keepingAlive.Add(1)
+		go tx.keepAlive(conn.txKeepAlive)
 	}
+	return tx
 }
 
 // Tx is a transaction
@@ -24,45 +77,325 @@ type Tx struct {
 	Done          bool
 	TransactionID *string
 	conn          *Connection
+	ctx           context.Context
+	mu            sync.Mutex
+	savepoints    int
+	status        string
+	onCommit      []func()
+	onRollback    []func()
+
+	beganAt      time.Time
+	lastActivity atomic.Int64
+	expired      atomic.Bool
+	busy         sync.Mutex // Held while a statement is executed within the transaction
+	finished     chan struct{}
+	stopOnce     sync.Once
+	keepingAlive sync.WaitGroup
+}
+
+// BeganAt returns the time at which the transaction was begun.
+func (r *Tx) BeganAt() time.Time {
+	return r.beganAt
+}
+
+// LastActivity returns the time at which the last statement within the transaction completed.
+func (r *Tx) LastActivity() time.Time {
+	return time.Unix(0, r.lastActivity.Load())
+}
+
+func (r *Tx) touch() {
+	r.lastActivity.Store(time.Now().UnixNano())
 }
 
-// Commit the transaction
+// Commit the transaction, honouring the context it was begun with and the configured commit timeout.
 func (r *Tx) Commit() error {
+	hooks, err := r.commit()
+	runHooks(hooks)
+	return err
+}
+
+func (r *Tx) commit() ([]func(), error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	if r.Done {
-		return sql.ErrTxDone
+		return nil, sql.ErrTxDone
+	}
+	// The keepalive mustn't execute a statement while the transaction is completed.
+	r.busy.Lock()
+	defer r.busy.Unlock()
+	if err := r.checkExpired(); err != nil {
+		return r.finish(false), err
+	}
+	if r.ctx.Err() != nil {
+		hooks, _ := r.rollbackLocked()
+		return hooks, r.ctx.Err()
 	}
 
-	_, err := r.conn.rds.CommitTransaction(context.TODO(), &rdsdata.CommitTransactionInput{
+	ctx, cancel := withOptionalTimeout(r.ctx, r.conn.commitTimeout)
+	defer cancel()
+	event := r.conn.transactionEvent(TransactionCommit, aws.ToString(r.TransactionID))
+	ctx = r.conn.hooks.BeforeTransaction(ctx, event)
+	start := time.Now()
+	output, err := r.conn.rds.CommitTransaction(ctx, &rdsdata.CommitTransactionInput{
 		ResourceArn:   aws.String(r.conn.resourceARN),
 		SecretArn:     aws.String(r.conn.secretARN),
 		TransactionId: r.TransactionID,
 	})
-	if err != nil {
-		return err
+	r.conn.metrics.ObserveCall(CallCommitTransaction, time.Since(start), err)
+	if output != nil {
+		event.Status = aws.ToString(output.TransactionStatus)
 	}
-	if r.conn.tx == r {
-		r.conn.tx = nil
+	r.conn.hooks.AfterTransaction(ctx, event, err)
+	if isTransactionNotFound(err) {
+		err = r.expire(err)
+		return r.finish(false), err
 	}
-	r.Done = true
-	return nil
+	if err != nil && r.ctx.Err() != nil {
+		// The commit was abandoned along with the transaction, so it's rolled back.
+		hooks, _ := r.rollbackLocked()
+		return hooks, translateError(r.conn.dialect, err)
+	}
+	if err != nil {
+		return nil, translateError(r.conn.dialect, err)
+	}
+	r.status = aws.ToString(output.TransactionStatus)
+	r.logCompletion(ctx, "rds: transaction committed")
+	r.conn.metrics.ObserveTransaction(TransactionCommitted)
+	return r.finish(true), nil
 }
 
-// Rollback the transaction
+// Rollback the transaction. This isn't bound to the cancellation of the context the transaction
+// was begun with, as that is exactly when a rollback is required, but does honour the commit timeout.
 func (r *Tx) Rollback() error {
+	hooks, err := r.rollback()
+	runHooks(hooks)
+	return err
+}
+
+func (r *Tx) rollback() ([]func(), error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	if r.Done {
-		return sql.ErrTxDone
+		return nil, sql.ErrTxDone
 	}
-	_, err := r.conn.rds.RollbackTransaction(context.TODO(), &rdsdata.RollbackTransactionInput{
+	r.busy.Lock()
+	defer r.busy.Unlock()
+	return r.rollbackLocked()
+}
+
+// rollbackLocked is rollback, with the lock and busy held.
+func (r *Tx) rollbackLocked() ([]func(), error) {
+	if err := r.checkExpired(); err != nil {
+		// The Data API has already rolled the transaction back.
+		return r.finish(false), err
+	}
+
+	ctx, cancel := withOptionalTimeout(context.WithoutCancel(r.ctx), r.conn.commitTimeout)
+	defer cancel()
+	event := r.conn.transactionEvent(TransactionRollback, aws.ToString(r.TransactionID))
+	ctx = r.conn.hooks.BeforeTransaction(ctx, event)
+	start := time.Now()
+	output, err := r.conn.rds.RollbackTransaction(ctx, &rdsdata.RollbackTransactionInput{
 		ResourceArn:   aws.String(r.conn.resourceARN),
 		SecretArn:     aws.String(r.conn.secretARN),
 		TransactionId: r.TransactionID,
 	})
+	r.conn.metrics.ObserveCall(CallRollbackTransaction, time.Since(start), err)
+	if output != nil {
+		event.Status = aws.ToString(output.TransactionStatus)
+	}
+	r.conn.hooks.AfterTransaction(ctx, event, err)
+	if isTransactionNotFound(err) {
+		err = r.expire(err)
+		return r.finish(false), err
+	}
 	if err != nil {
-		return err
+		return nil, err
 	}
+	r.status = aws.ToString(output.TransactionStatus)
+	r.logCompletion(ctx, "rds: transaction rolled back")
+	r.conn.metrics.ObserveTransaction(TransactionRolledBack)
+	return r.finish(false), nil
+}
+
+// TransactionStatus as returned by the Data API once the transaction was committed or rolled back.
+func (r *Tx) TransactionStatus() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.status
+}
+
+// OnCommit registers a callback, which is called once the Data API confirms that the transaction
+// was committed. Returns sql.ErrTxDone if the transaction has already completed.
+func (r *Tx) OnCommit(fn func()) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.Done {
+		return sql.ErrTxDone
+	}
+	r.onCommit = append(r.onCommit, fn)
+	return nil
+}
+
+// OnRollback registers a callback, which is called once the transaction was rolled back, either
+// explicitly or because the Data API terminated it. Returns sql.ErrTxDone if the transaction has
+// already completed.
+func (r *Tx) OnRollback(fn func()) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.Done {
+		return sql.ErrTxDone
+	}
+	r.onRollback = append(r.onRollback, fn)
+	return nil
+}
+
+// finish the transaction, detaching it from its connection. Returns the callbacks to run for
+// the outcome, once the lock is released.
+func (r *Tx) finish(committed bool) []func() {
+	r.stopKeepAlive()
 	if r.conn.tx == r {
 		r.conn.tx = nil
 	}
 	r.Done = true
+
+	hooks := r.onRollback
+	if committed {
+		hooks = r.onCommit
+	}
+	r.onCommit = nil
+	r.onRollback = nil
+	return hooks
+}
+
+func runHooks(hooks []func()) {
+	for _, hook := range hooks {
+		hook()
+	}
+}
+
+func (r *Tx) logCompletion(ctx context.Context, msg string) {
+	r.conn.log.transaction(ctx, msg,
+		slog.String("transaction_id", aws.ToString(r.TransactionID)),
+		slog.String("status", r.status),
+		slog.Duration("duration", time.Since(r.beganAt)))
+}
+
+// checkExpired fails fast with ErrTransactionExpired if the Data API will already have terminated
+// the transaction, marking the connection as bad.
+func (r *Tx) checkExpired() error {
+	if r.expired.Load() {
+		return ErrTransactionExpired
+	}
+	if r.beganAt.IsZero() {
+		return nil
+	}
+	now := time.Now()
+	if now.Sub(r.beganAt) >= TransactionMaxLifetime || now.Sub(r.LastActivity()) >= TransactionIdleTimeout {
+		return r.expire(nil)
+	}
 	return nil
 }
+
+// expire the transaction, marking the connection as bad so that it's discarded rather than reset.
+func (r *Tx) expire(cause error) error {
+	if !r.expired.Swap(true) {
+		r.conn.log.transaction(context.WithoutCancel(r.ctx), "rds: transaction expired",
+			slog.String("transaction_id", aws.ToString(r.TransactionID)),
+			slog.Duration("duration", time.Since(r.beganAt)),
+			slog.Any("error", cause))
+		r.conn.metrics.ObserveTransaction(TransactionExpired)
+	}
+	r.conn.bad.Store(true)
+	r.stopKeepAlive()
+	if cause != nil {
+		return fmt.Errorf("%w: %w", ErrTransactionExpired, cause)
+	}
+	return ErrTransactionExpired
+}
+
+// stopKeepAlive signals the keepalive, if any, to stop. It's safe to call more than once.
+func (r *Tx) stopKeepAlive() {
+	r.stopOnce.Do(func() {
+		if r.finished != nil {
+			close(r.finished)
+		}
+	})
+}
+
+// isFinished reports whether the keepalive was signalled to stop.
+func (r *Tx) isFinished() bool {
+	select {
+	case <-r.finished:
+		return true
+	default:
+		return false
+	}
+}
+
+// abandon the transaction along with its connection, stopping its keepalive and waiting for it to
+// return, whether or not the transaction was rolled back.
+func (r *Tx) abandon() {
+	r.stopKeepAlive()
+	r.keepingAlive.Wait()
+}
+
+// keepAlive executes a statement within the transaction whenever it's been idle for the interval,
+// preventing the Data API from terminating it. It stops once the transaction completes or expires,
+// or its connection is closed or marked as bad.
+func (r *Tx) keepAlive(interval time.Duration) {
+	defer r.keepingAlive.Done()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.finished:
+			return
+		case <-ticker.C:
+		}
+		if r.checkExpired() != nil {
+			return
+		}
+		if time.Since(r.LastActivity()) < interval || !r.busy.TryLock() {
+			continue
+		}
+		if r.isFinished() {
+			r.busy.Unlock()
+			return
+		}
+		_, err := r.conn.executeStatement(context.WithoutCancel(r.ctx), &rdsdata.ExecuteStatementInput{
+			ResourceArn:   aws.String(r.conn.resourceARN),
+			SecretArn:     aws.String(r.conn.secretARN),
+			Database:      aws.String(r.conn.database),
+			Sql:           aws.String("/* keepalive */ SELECT 1"),
+			TransactionId: r.TransactionID,
+		})
+		if err == nil {
+			r.touch()
+		} else if isTransactionNotFound(err) {
+			_ = r.expire(err)
+		}
+		r.busy.Unlock()
+	}
+}
+
+// isTransactionNotFound reports whether the Data API no longer knows of the transaction.
+func isTransactionNotFound(err error) bool {
+	if err == nil {
+		return false
+	}
+	var txNotFound *types.TransactionNotFoundException
+	if errors.As(err, &txNotFound) {
+		return true
+	}
+	var notFound *types.NotFoundException
+	return errors.As(err, &notFound) && strings.Contains(strings.ToLower(notFound.ErrorMessage()), "transaction")
+}
+
+// withOptionalTimeout applies the timeout to the context, if one is set.
+func withOptionalTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
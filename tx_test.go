@@ -0,0 +1,323 @@
+package rds_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rdsdata"
+	"github.com/aws/aws-sdk-go-v2/service/rdsdata/types"
+	"github.com/golang/mock/gomock"
+	"github.com/krotscheck/go-rds-driver"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// ExpectBeginTransaction can be used whenever we're mocking out a new transaction
+func ExpectBeginTransaction(mockRDS *MockAWSClientInterface, transactionID string) *gomock.Call {
+	return mockRDS.EXPECT().
+		BeginTransaction(gomock.Any(), gomock.Any()).
+		Return(&rdsdata.BeginTransactionOutput{TransactionId: aws.String(transactionID)}, nil)
+}
+
+func Test_Tx(t *testing.T) {
+	Convey("Tx", t, func() {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		mockRDS := NewMockAWSClientInterface(ctrl)
+		conf := rds.NewConfig("resourceARN", "secretARN", "database", "region")
+		conf.CommitTimeout = time.Second
+
+		conn := rds.NewConnection(context.Background(), mockRDS, conf, rds.NewMySQL(conf)).(*rds.Connection)
+		ExpectBeginTransaction(mockRDS, "transactionID")
+
+		Convey("Commit honours the begin context and timeout", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			tx, err := conn.BeginTx(ctx, driver.TxOptions{})
+			So(err, ShouldBeNil)
+
+			mockRDS.EXPECT().
+				CommitTransaction(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, _ *rdsdata.CommitTransactionInput, _ ...func(*rdsdata.Options)) (*rdsdata.CommitTransactionOutput, error) {
+					_, hasDeadline := ctx.Deadline()
+					So(hasDeadline, ShouldBeTrue)
+					cancel()
+					<-ctx.Done()
+					return nil, ctx.Err()
+				})
+
+			rolledBack := make(chan struct{})
+			mockRDS.EXPECT().
+				RollbackTransaction(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, _ *rdsdata.RollbackTransactionInput, _ ...func(*rdsdata.Options)) (*rdsdata.RollbackTransactionOutput, error) {
+					close(rolledBack)
+					return &rdsdata.RollbackTransactionOutput{}, nil
+				})
+
+			So(tx.Commit(), ShouldEqual, context.Canceled)
+			<-rolledBack
+		})
+
+		Convey("Completed transactions ignore the begin context", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+
+			tx, err := conn.BeginTx(ctx, driver.TxOptions{})
+			So(err, ShouldBeNil)
+
+			mockRDS.EXPECT().
+				CommitTransaction(gomock.Any(), gomock.Any()).
+				Return(&rdsdata.CommitTransactionOutput{}, nil)
+			So(tx.Commit(), ShouldBeNil)
+			cancel()
+			So(tx.Rollback(), ShouldEqual, sql.ErrTxDone)
+		})
+
+		Convey("Cancelling the begin context rolls back before the connection is next used", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+
+			tx, err := conn.BeginTx(ctx, driver.TxOptions{})
+			So(err, ShouldBeNil)
+
+			rollback := mockRDS.EXPECT().
+				RollbackTransaction(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, input *rdsdata.RollbackTransactionInput, _ ...func(*rdsdata.Options)) (*rdsdata.RollbackTransactionOutput, error) {
+					So(ctx.Err(), ShouldBeNil)
+					return &rdsdata.RollbackTransactionOutput{}, nil
+				})
+			ExpectStatement(mockRDS, "SELECT 1").
+				DoAndReturn(func(_ context.Context, input *rdsdata.ExecuteStatementInput, _ ...func(*rdsdata.Options)) (*rdsdata.ExecuteStatementOutput, error) {
+					So(input.TransactionId, ShouldBeNil)
+					return &rdsdata.ExecuteStatementOutput{}, nil
+				}).
+				After(rollback)
+
+			cancel()
+			_, err = conn.ExecContext(context.Background(), "SELECT 1", nil)
+			So(err, ShouldBeNil)
+
+			So(tx.Commit(), ShouldEqual, sql.ErrTxDone)
+			So(conn.ResetSession(context.Background()), ShouldBeNil)
+		})
+
+		Convey("Committing after cancelling the begin context rolls back", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+
+			tx, err := conn.BeginTx(ctx, driver.TxOptions{})
+			So(err, ShouldBeNil)
+
+			mockRDS.EXPECT().RollbackTransaction(gomock.Any(), gomock.Any()).Return(&rdsdata.RollbackTransactionOutput{}, nil)
+			cancel()
+			So(tx.Commit(), ShouldEqual, context.Canceled)
+			_, err = conn.CurrentTx()
+			So(err, ShouldEqual, rds.ErrNoTransaction)
+		})
+
+		Convey("Cancelling the begin context during a statement", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			_, err := conn.BeginTx(ctx, driver.TxOptions{})
+			So(err, ShouldBeNil)
+
+			ExpectStatement(mockRDS, "UPDATE accounts SET balance = 0").
+				DoAndReturn(func(ctx context.Context, _ *rdsdata.ExecuteStatementInput, _ ...func(*rdsdata.Options)) (*rdsdata.ExecuteStatementOutput, error) {
+					cancel()
+					<-ctx.Done()
+					return nil, ctx.Err()
+				})
+			rollback := mockRDS.EXPECT().RollbackTransaction(gomock.Any(), gomock.Any()).Return(&rdsdata.RollbackTransactionOutput{}, nil)
+			mockRDS.EXPECT().
+				BeginTransaction(gomock.Any(), gomock.Any()).
+				Return(&rdsdata.BeginTransactionOutput{TransactionId: aws.String("nextTransactionID")}, nil).
+				After(rollback)
+
+			_, err = conn.ExecContext(ctx, "UPDATE accounts SET balance = 0", nil)
+			So(errors.Is(err, context.Canceled), ShouldBeTrue)
+
+			// The transaction is rolled back by the next use of the connection, rather than concurrently.
+			next, err := conn.BeginTx(context.Background(), driver.TxOptions{})
+			So(err, ShouldBeNil)
+			So(aws.ToString(next.(*rds.Tx).TransactionID), ShouldEqual, "nextTransactionID")
+		})
+
+		Convey("Lifetime", func() {
+			tx, err := conn.BeginTx(context.Background(), driver.TxOptions{})
+			So(err, ShouldBeNil)
+			dTx := tx.(*rds.Tx)
+			So(dTx.BeganAt(), ShouldHappenWithin, time.Second, time.Now())
+
+			Convey("Tracks activity", func() {
+				dTx.SetLastActivity(time.Now().Add(-time.Minute))
+				ExpectStatement(mockRDS, "SELECT 1").Return(&rdsdata.ExecuteStatementOutput{}, nil)
+
+				_, err := conn.ExecContext(context.Background(), "SELECT 1", nil)
+				So(err, ShouldBeNil)
+				So(dTx.LastActivity(), ShouldHappenWithin, time.Second, time.Now())
+			})
+
+			Convey("Fails fast once idle", func() {
+				dTx.SetLastActivity(time.Now().Add(-rds.TransactionIdleTimeout))
+
+				_, err := conn.ExecContext(context.Background(), "SELECT 1", nil)
+				So(err, ShouldEqual, rds.ErrTransactionExpired)
+				So(conn.ResetSession(context.Background()), ShouldEqual, driver.ErrBadConn)
+				So(conn.IsValid(), ShouldBeFalse)
+				So(tx.Rollback(), ShouldEqual, rds.ErrTransactionExpired)
+				So(conn.Close(), ShouldBeNil)
+			})
+
+			Convey("Expired by the Data API", func() {
+				ExpectStatement(mockRDS, "SELECT 1").Return(nil, &types.TransactionNotFoundException{Message: aws.String("Transaction transactionID is not found")})
+
+				_, err := conn.ExecContext(context.Background(), "SELECT 1", nil)
+				So(errors.Is(err, rds.ErrTransactionExpired), ShouldBeTrue)
+				var notFound *types.TransactionNotFoundException
+				So(errors.As(err, &notFound), ShouldBeTrue)
+				So(conn.ResetSession(context.Background()), ShouldEqual, driver.ErrBadConn)
+				So(tx.Commit(), ShouldEqual, rds.ErrTransactionExpired)
+			})
+		})
+
+		Convey("Hooks", func() {
+			tx, err := conn.BeginTx(context.Background(), driver.TxOptions{})
+			So(err, ShouldBeNil)
+			current, err := conn.CurrentTx()
+			So(err, ShouldBeNil)
+			So(current, ShouldEqual, tx)
+
+			var committed, rolledBack int
+			So(current.OnCommit(func() { committed++ }), ShouldBeNil)
+			So(current.OnRollback(func() { rolledBack++ }), ShouldBeNil)
+
+			Convey("Fire once the commit is confirmed", func() {
+				gomock.InOrder(
+					mockRDS.EXPECT().
+						CommitTransaction(gomock.Any(), gomock.Any()).
+						Return(nil, errors.New("service unavailable")),
+					mockRDS.EXPECT().
+						CommitTransaction(gomock.Any(), gomock.Any()).
+						Return(&rdsdata.CommitTransactionOutput{TransactionStatus: aws.String("Transaction Committed")}, nil),
+				)
+
+				So(tx.Commit(), ShouldNotBeNil)
+				So(committed, ShouldEqual, 0)
+				So(current.TransactionStatus(), ShouldEqual, "")
+
+				So(tx.Commit(), ShouldBeNil)
+				So(tx.Commit(), ShouldEqual, sql.ErrTxDone)
+				So(committed, ShouldEqual, 1)
+				So(rolledBack, ShouldEqual, 0)
+				So(current.TransactionStatus(), ShouldEqual, "Transaction Committed")
+				So(current.OnCommit(func() {}), ShouldEqual, sql.ErrTxDone)
+			})
+
+			Convey("Fire once rolled back", func() {
+				mockRDS.EXPECT().
+					RollbackTransaction(gomock.Any(), gomock.Any()).
+					Return(&rdsdata.RollbackTransactionOutput{TransactionStatus: aws.String("Rollback Complete")}, nil)
+
+				So(tx.Rollback(), ShouldBeNil)
+				So(tx.Rollback(), ShouldEqual, sql.ErrTxDone)
+				So(committed, ShouldEqual, 0)
+				So(rolledBack, ShouldEqual, 1)
+				So(current.TransactionStatus(), ShouldEqual, "Rollback Complete")
+			})
+
+			Convey("Fire on rollback when expired", func() {
+				mockRDS.EXPECT().
+					CommitTransaction(gomock.Any(), gomock.Any()).
+					Return(nil, &types.TransactionNotFoundException{Message: aws.String("Transaction transactionID is not found")})
+
+				So(errors.Is(tx.Commit(), rds.ErrTransactionExpired), ShouldBeTrue)
+				So(committed, ShouldEqual, 0)
+				So(rolledBack, ShouldEqual, 1)
+			})
+
+			Convey("Are reachable through the connection", func() {
+				mockRDS.EXPECT().RollbackTransaction(gomock.Any(), gomock.Any()).Return(&rdsdata.RollbackTransactionOutput{}, nil)
+				So(tx.Rollback(), ShouldBeNil)
+
+				_, err := conn.CurrentTx()
+				So(err, ShouldEqual, rds.ErrNoTransaction)
+			})
+		})
+
+		Convey("Keepalive", func() {
+			keepAliveConf := rds.NewConfig("resourceARN", "secretARN", "database", "region")
+			keepAliveConf.TxKeepAlive = 10 * time.Millisecond
+			keepAliveConn := rds.NewConnection(context.Background(), mockRDS, keepAliveConf, rds.NewMySQL(keepAliveConf)).(*rds.Connection)
+
+			keptAlive := make(chan string, 100)
+			// Once the transaction is completed, the Data API no longer knows of it.
+			var completed atomic.Bool
+			ExpectStatement(mockRDS, "/* keepalive */ SELECT 1").
+				MinTimes(1).
+				DoAndReturn(func(_ context.Context, input *rdsdata.ExecuteStatementInput, _ ...func(*rdsdata.Options)) (*rdsdata.ExecuteStatementOutput, error) {
+					keptAlive <- aws.ToString(input.TransactionId)
+					if completed.Load() {
+						return nil, &types.TransactionNotFoundException{Message: aws.String("Transaction transactionID is not found")}
+					}
+					return &rdsdata.ExecuteStatementOutput{}, nil
+				})
+
+			tx, err := keepAliveConn.BeginTx(context.Background(), driver.TxOptions{})
+			So(err, ShouldBeNil)
+
+			select {
+			case transactionID := <-keptAlive:
+				So(transactionID, ShouldEqual, "transactionID")
+			case <-time.After(time.Second):
+				t.Fatal("transaction was not kept alive")
+			}
+			// stopsKeepingAlive asserts that no further keepalive is executed.
+			stopsKeepingAlive := func() {
+				time.Sleep(50 * time.Millisecond)
+				for len(keptAlive) > 0 {
+					<-keptAlive
+				}
+				time.Sleep(50 * time.Millisecond)
+				So(keptAlive, ShouldHaveLength, 0)
+			}
+
+			Convey("Stops once the transaction is rolled back", func() {
+				mockRDS.EXPECT().RollbackTransaction(gomock.Any(), gomock.Any()).Return(&rdsdata.RollbackTransactionOutput{}, nil)
+				So(tx.Rollback(), ShouldBeNil)
+				stopsKeepingAlive()
+			})
+
+			Convey("Doesn't run while the transaction is committed", func() {
+				mockRDS.EXPECT().CommitTransaction(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ *rdsdata.CommitTransactionInput, _ ...func(*rdsdata.Options)) (*rdsdata.CommitTransactionOutput, error) {
+						completed.Store(true)
+						time.Sleep(100 * time.Millisecond)
+						return &rdsdata.CommitTransactionOutput{TransactionStatus: aws.String("Transaction Committed")}, nil
+					})
+				So(tx.Commit(), ShouldBeNil)
+				stopsKeepingAlive()
+				So(keepAliveConn.IsValid(), ShouldBeTrue)
+			})
+
+			Convey("Stops once the connection is closed, even though the rollback failed", func() {
+				mockRDS.EXPECT().RollbackTransaction(gomock.Any(), gomock.Any()).Return(nil, errors.New("rollback failed"))
+				So(keepAliveConn.Close(), ShouldNotBeNil)
+				So(keepAliveConn.Close(), ShouldEqual, rds.ErrClosed)
+				stopsKeepingAlive()
+			})
+
+			Convey("Stops once the connection is reset, even though the rollback failed", func() {
+				mockRDS.EXPECT().RollbackTransaction(gomock.Any(), gomock.Any()).Return(nil, errors.New("rollback failed"))
+				So(keepAliveConn.ResetSession(context.Background()), ShouldEqual, driver.ErrBadConn)
+				stopsKeepingAlive()
+
+				mockRDS.EXPECT().RollbackTransaction(gomock.Any(), gomock.Any()).Return(&rdsdata.RollbackTransactionOutput{}, nil)
+				So(keepAliveConn.Close(), ShouldBeNil)
+			})
+		})
+	})
+}